@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// loadAdminIPAllowlist parses ADMIN_IP_ALLOWLIST into CIDR prefixes. A bare IP is
+// treated as a single-host prefix, so "203.0.113.7" and "203.0.113.7/32" are equivalent.
+func (c *Config) loadAdminIPAllowlist() error {
+	prefixes := make([]netip.Prefix, 0, len(c.AdminIPAllowlist))
+	for _, entry := range c.AdminIPAllowlist {
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return fmt.Errorf("invalid CIDR %q: %w", entry, err)
+		}
+		prefix = prefix.Masked()
+		// client addresses are unmapped, so an IPv4-mapped prefix must be too
+		if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+			prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	c.adminIPPrefixes = prefixes
+	return nil
+}
+
+// IsAdminIPAllowed reports whether a client IP may reach admin routes. The IP must be
+// the trusted-proxy-aware client address; an empty allowlist allows everyone.
+func IsAdminIPAllowed(ip string) bool {
+	if len(AppConfig.adminIPPrefixes) == 0 {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range AppConfig.adminIPPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package config
+
+import "testing"
+
+func TestIsAdminIPAllowed(t *testing.T) {
+	AppConfig = &Config{AdminIPAllowlist: []string{"203.0.113.7", "10.0.0.0/8", "::ffff:192.168.0.0/112", "2001:db8::/32"}}
+	if err := AppConfig.loadAdminIPAllowlist(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"203.0.113.7", true},
+		{"::ffff:203.0.113.7", true},
+		{"203.0.113.8", false},
+		{"10.20.30.40", true},
+		{"192.168.0.5", true},
+		{"::ffff:192.168.0.5", true},
+		{"192.169.0.5", false},
+		{"2001:db8::1", true},
+		{"2001:db9::1", false},
+		{"not-an-ip", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := IsAdminIPAllowed(tt.ip); got != tt.want {
+				t.Errorf("IsAdminIPAllowed(%q) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAdminIPAllowlistInvalid(t *testing.T) {
+	c := Config{AdminIPAllowlist: []string{"10.0.0.0/33"}}
+	if err := c.loadAdminIPAllowlist(); err == nil {
+		t.Error("expected an error for an invalid CIDR")
+	}
+}
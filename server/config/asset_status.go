@@ -0,0 +1,64 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// defaultAssetStatusTransitions is used when ASSET_STATUS_TRANSITIONS_FILE is not set
+var defaultAssetStatusTransitions = map[string][]string{
+	"available":   {"in-use", "maintenance", "retired"},
+	"in-use":      {"available", "maintenance"},
+	"maintenance": {"available", "retired"},
+	"retired":     {},
+}
+
+// loadAssetStatusTransitions reads the status state machine from a JSON file mapping
+// each status to the statuses it may move to, e.g. {"available": ["in-use"], "in-use": []}
+func (c *Config) loadAssetStatusTransitions() error {
+	if c.AssetStatusTransitionsFile == "" {
+		c.AssetStatusTransitions = defaultAssetStatusTransitions
+		return nil
+	}
+
+	data, err := os.ReadFile(c.AssetStatusTransitionsFile)
+	if err != nil {
+		return err
+	}
+
+	var transitions map[string][]string
+	if err := json.Unmarshal(data, &transitions); err != nil {
+		return fmt.Errorf("parse %s: %w", c.AssetStatusTransitionsFile, err)
+	}
+	if len(transitions) == 0 {
+		return fmt.Errorf("%s defines no statuses", c.AssetStatusTransitionsFile)
+	}
+	for from, targets := range transitions {
+		for _, to := range targets {
+			if _, ok := transitions[to]; !ok {
+				return fmt.Errorf("status %q transitions to undefined status %q", from, to)
+			}
+		}
+	}
+
+	c.AssetStatusTransitions = transitions
+	return nil
+}
+
+// CanTransition reports whether an asset may move from one status to another
+func CanTransition(from, to string) bool {
+	return slices.Contains(AppConfig.AssetStatusTransitions[from], to)
+}
+
+// ValidateStatusTransition returns a descriptive error when the transition is not allowed
+func ValidateStatusTransition(from, to string) error {
+	if _, ok := AppConfig.AssetStatusTransitions[to]; !ok {
+		return fmt.Errorf("unknown asset status %q", to)
+	}
+	if !CanTransition(from, to) {
+		return fmt.Errorf("asset status cannot change from %q to %q", from, to)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package config
+
+// Supported BARCODE_FORMAT values
+const (
+	BarcodeCode128 = "CODE128"
+	BarcodeEAN13   = "EAN13"
+)
+
+// ValidBarcode reports whether a scanned code is well-formed for the configured format
+func ValidBarcode(code string) bool {
+	switch AppConfig.BarcodeFormat {
+	case BarcodeEAN13:
+		return validEAN13(code)
+	default:
+		return validCode128(code)
+	}
+}
+
+// validCode128 accepts non-empty printable ASCII, which Code 128 can encode
+func validCode128(code string) bool {
+	if code == "" {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < 0x20 || code[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// validEAN13 checks length, digits and the trailing check digit
+func validEAN13(code string) bool {
+	if len(code) != 13 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+		if i == 12 {
+			break
+		}
+		digit := int(code[i] - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return (10-sum%10)%10 == int(code[12]-'0')
+}
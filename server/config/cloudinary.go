@@ -0,0 +1,77 @@
+package config
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DirectUploadSignature is what the browser needs to upload straight to Cloudinary.
+// Folder and AllowedFormats must be sent unchanged, since they are part of the signature.
+type DirectUploadSignature struct {
+	Timestamp      int64    `json:"timestamp"`
+	Signature      string   `json:"signature"`
+	APIKey         string   `json:"api_key"`
+	Folder         string   `json:"folder"`
+	AllowedFormats []string `json:"allowed_formats"`
+}
+
+// SignDirectUpload signs a Cloudinary upload for a media category (images, videos,
+// documents) into that category's folder, restricted to its allowed formats
+func SignDirectUpload(category string, timestamp int64) (DirectUploadSignature, error) {
+	if !AppConfig.CloudAllowDirectUpload {
+		return DirectUploadSignature{}, errors.New("direct uploads are disabled")
+	}
+
+	allowed := allowedTypesFor(category)
+	if allowed == nil {
+		return DirectUploadSignature{}, fmt.Errorf("unknown upload category %q", category)
+	}
+
+	formats := make([]string, 0, len(allowed))
+	for _, contentType := range allowed {
+		_, format, _ := strings.Cut(contentType, "/")
+		if format == "jpeg" {
+			format = "jpg"
+		}
+		if !slices.Contains(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+
+	folder := AppConfig.CloudFolder + "/" + category
+	params := map[string]string{
+		"allowed_formats": strings.Join(formats, ","),
+		"folder":          folder,
+		"timestamp":       strconv.FormatInt(timestamp, 10),
+	}
+	return DirectUploadSignature{
+		Timestamp:      timestamp,
+		Signature:      signCloudinaryParams(params, AppConfig.CloudSecret),
+		APIKey:         AppConfig.CloudApiKey,
+		Folder:         folder,
+		AllowedFormats: formats,
+	}, nil
+}
+
+// signCloudinaryParams computes Cloudinary's upload signature: the SHA-1 of the params
+// sorted by name as key=value pairs joined with &, followed by the API secret
+func signCloudinaryParams(params map[string]string, secret string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + params[key]
+	}
+
+	sum := sha1.Sum([]byte(strings.Join(pairs, "&") + secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSignCloudinaryParams(t *testing.T) {
+	// example from Cloudinary's upload signature documentation
+	params := map[string]string{
+		"timestamp": "1315060510",
+		"public_id": "sample_image",
+		"eager":     "w_400,h_300,c_pad|w_260,h_200,c_crop",
+	}
+	want := "bfd09f95f331f558cbd1320e67aa8d488770583e"
+
+	if got := signCloudinaryParams(params, "abcd"); got != want {
+		t.Errorf("signCloudinaryParams() = %q, want %q", got, want)
+	}
+}
+
+func TestSignDirectUpload(t *testing.T) {
+	AppConfig = &Config{
+		CloudAllowDirectUpload: true,
+		CloudSecret:            "test-secret",
+		CloudApiKey:            "test-key",
+		CloudFolder:            "asset_management_app",
+		AllowedImageTypes:      []string{"image/jpeg", "image/png"},
+	}
+
+	got, err := SignDirectUpload("images", 1700000000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// sha1("allowed_formats=jpg,png&folder=asset_management_app/images&timestamp=1700000000test-secret")
+	if want := "e5ddb7ff190dc48b7e22b492f14a826495ab3cce"; got.Signature != want {
+		t.Errorf("Signature = %q, want %q", got.Signature, want)
+	}
+	if got.Folder != "asset_management_app/images" {
+		t.Errorf("Folder = %q", got.Folder)
+	}
+	if !slices.Equal(got.AllowedFormats, []string{"jpg", "png"}) {
+		t.Errorf("AllowedFormats = %v", got.AllowedFormats)
+	}
+	if got.APIKey != "test-key" || got.Timestamp != 1700000000 {
+		t.Errorf("APIKey/Timestamp = %q/%d", got.APIKey, got.Timestamp)
+	}
+}
+
+func TestSignDirectUploadRejected(t *testing.T) {
+	AppConfig = &Config{CloudAllowDirectUpload: true, CloudSecret: "test-secret"}
+	if _, err := SignDirectUpload("archives", 1700000000); err == nil {
+		t.Error("expected an error for an unknown category")
+	}
+
+	AppConfig.CloudAllowDirectUpload = false
+	if _, err := SignDirectUpload("images", 1700000000); err == nil {
+		t.Error("expected an error when direct uploads are disabled")
+	}
+}
@@ -1,8 +1,17 @@
 package config
 
 import (
+	"crypto/rsa"
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"net/mail"
+	"net/netip"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -11,128 +20,456 @@ import (
 // Config holds all environment configuration
 type Config struct {
 	// Server settings
-	ServerPort string
-	ServerHost string
+	ServerPort                string
+	ServerHost                string
+	MaxConcurrentRequests     int
+	MaxRequestBodySize        int64
+	maxRequestBodySizeSet     bool
+	RequestTimeout            time.Duration
+	RequestTimeoutExemptPaths []string
+	JSONMaxDepth              int
+	JSONMaxFields             int
+	CompressionEnabled        bool
+	CompressionMinSize        int
+	ETagEnabled               bool
+	ErrorCodeFormat           string
+	PaginationStyle           string
+	DefaultSortOrders         map[string]string
+	DeprecatedEndpoints       map[string]string
+	ResponseEnvelopeEnabled   bool
+
+	ServerReadTimeout       time.Duration
+	ServerWriteTimeout      time.Duration
+	ServerIdleTimeout       time.Duration
+	ServerReadHeaderTimeout time.Duration
+	LongRunningReadTimeout  time.Duration
+	LongRunningWriteTimeout time.Duration
+	StartupWaitTimeout      time.Duration
+	StartupWaitBackoff      time.Duration
+
+	TLSMinVersion string
+	TLSCertPath   string
+	TLSKeyPath    string
+
+	// Outbound HTTP client settings
+	HTTPClientTimeout             time.Duration
+	HTTPClientDialTimeout         time.Duration
+	HTTPClientTLSHandshakeTimeout time.Duration
+	HTTPClientMaxRetries          int
+	HTTPClientRetryBackoff        time.Duration
+
+	// Health check settings
+	HealthDegradedLatencyMs      int
+	HealthMaxConsecutiveFailures int
+	LatencyBudgetMs              map[string]int
 
 	// Security settings
-	ApiKeys             string
-	AllowedOrigins      []string
-	RateLimitAttempts   int
-	RateLimitDuration   time.Duration
-	SkippedApiEndpoints []string
-	TrustedProxies      []string
-	CookieDomain        string
+	ApiKeys                 string
+	AllowedOrigins          []string
+	RateLimitAttempts       int
+	RateLimitDuration       time.Duration
+	RateLimitHeadersEnabled bool
+	ExportRateLimitAttempts int
+	ExportRateLimitDuration time.Duration
+	RateLimitExemptApiKeys  []string
+	TenantRateLimit         RateLimitTier
+	SkippedApiEndpoints     []string
+	TrustedProxies          []string
+	CookieDomain            string
+	TrackSessions           bool
+	MaxSessionsPerUser      int
+	SessionLimitMode        string
+
+	// Admin safeguard settings
+	AdminBulkDeleteThreshold int
+	AdminHighImpactAction    string
+	AdminIPAllowlist         []string
+	adminIPPrefixes          []netip.Prefix
+
+	// Logging settings
+	AccessLogEnabled      bool
+	AccessLogRedactFields []string
 
 	// Database settings
-	DatabaseRootURL string
-	DatabaseName    string
-	DatabaseURL     string
+	DatabaseRootURL      string
+	DatabaseName         string
+	DatabaseURL          string
+	DBLogQueries         bool
+	DBSlowQueryThreshold time.Duration
+	AnonymizeFields      []string
+
+	// Backup settings
+	BackupEnabled       bool
+	BackupSchedule      string
+	BackupTargetDir     string
+	BackupRetentionDays int
 
 	// Redis settings
 	RedisAddress  string
 	RedisPassword string
 
+	// Cache settings
+	DashboardCacheTTL time.Duration
+	CacheTTL          time.Duration
+
 	// JWT settings
-	AccessTokenSecret  string
-	RefreshTokenSecret string
+	AccessTokenSecret     string
+	RefreshTokenSecret    string
+	JWTAlgorithm          string
+	JWTPrivateKeyPath     string
+	JWTPrivateKeyPEM      string
+	JWTPublicKeyPath      string
+	JWTPublicKeyPEM       string
+	JWTPrivateKey         *rsa.PrivateKey
+	JWTPublicKey          *rsa.PublicKey
+	JWTIssuer             string
+	JWTAudience           string
+	RefreshTokenRotation  bool
+	SlidingSessionEnabled bool
+	SlidingSessionWindow  time.Duration
+	TokenCleanupInterval  time.Duration
+	PasswordResetTTL      time.Duration
+	AllowImpersonation    bool
+	ImpersonationTTL      time.Duration
 
 	// Email settings
-	SMTPHost     string
-	SMTPPort     int
-	SMTPEmail    string
-	SMTPPassword string
+	SMTPHost           string
+	SMTPPort           int
+	SMTPEmail          string
+	SMTPPassword       string
+	EmailFromName      string
+	EmailReplyTo       string
+	EmailBatchSize     int
+	EmailBatchInterval time.Duration
 
 	// App settings
-	AppName     string
-	AppEnv      string
-	FrontendURL string
+	AppName      string
+	AppEnv       string
+	FeatureFlags map[string]bool
+	FrontendURL  string
+
+	// Media settings
+	DefaultAvatarURL       string
+	DocumentPlaceholderURL string
+	VideoPlaceholderURL    string
+	GeneratePDFThumbnails  bool
+
+	// Account settings
+	GDPRGraceDays            int
+	DefaultUserStorageQuota  int64
+	InvitationTTL            time.Duration
+	RequireEmailVerification bool
+	DefaultUserRole          string
+	DefaultSelfRegisterRole  string
+
+	// Asset settings
+	WarrantyNotifyDaysBefore     []int
+	DefaultCurrency              string
+	BulkBatchSize                int
+	BulkConfirmThreshold         int
+	DuplicateSimilarityThreshold float64
+	LabelReprintAnomalyThreshold int
+	LabelReprintAnomalyWindow    time.Duration
+	AssetEditLockTTL             time.Duration
+	NestedCollectionLimit        int
+	BarcodeFormat                string
+	MaintenanceReminderLeadTime  time.Duration
+	RequireAssetApproval         bool
+	AssetApproverRole            string
+
+	AssetStatusTransitionsFile string
+	AssetStatusTransitions     map[string][]string
+
+	// Location settings
+	MaxLocationDepth int
+	GeofenceEnabled  bool
+	GeofenceMinLat   float64
+	GeofenceMaxLat   float64
+	GeofenceMinLng   float64
+	GeofenceMaxLng   float64
 
 	// cloudinary settings
-	CloudName            string
-	CloudSecret          string
-	CloudApiKey          string
-	CloudFolder          string
-	AllowedImageTypes    []string
-	AllowedVideoTypes    []string
-	AllowedDocumentTypes []string
-	MaxFileSize          map[string]int64
+	CloudName              string
+	CloudSecret            string
+	CloudApiKey            string
+	CloudFolder            string
+	CloudAsyncTransform    bool
+	CloudNotificationURL   string
+	CloudAllowDirectUpload bool
+	AllowedImageTypes      []string
+	AllowedVideoTypes      []string
+	AllowedDocumentTypes   []string
+	MaxFileSize            map[string]int64
+	UploadSniffContentType bool
+	MaxImageWidth          int
+	MaxImageHeight         int
+	ResizeOnUpload         bool
+	DedupeUploads          bool
+	MaxConcurrentUploads   int
+	UploadQueueSize        int
+	PreviewMaxWidth        int
+	PreviewMaxHeight       int
 
 	// google oauth settings
 	GoogleClientID      string
 	GoogleClientSecret  string
 	GoogleRedirectURL   string
 	FrontendRedirectURL string
+	OAuthStateTTL       time.Duration
 
 	// stripe settings
-	StripeWebhookSecret  string
-	StripeCancelUrlDev   string
-	StripeSuccessUrlDev  string
-	StripeCancelUrlProd  string
-	StripeSuccessUrlProd string
-	StripeSecretKey      string
-	StripePublishableKey string
+	StripeWebhookSecret            string
+	StripeWebhookTolerance         time.Duration
+	StripeCancelUrlDev             string
+	StripeSuccessUrlDev            string
+	StripeCancelUrlProd            string
+	StripeSuccessUrlProd           string
+	StripeSecretKey                string
+	StripePublishableKey           string
+	StripePortalReturnURL          string
+	StripePortalFeatures           []string
+	StripeCheckoutLocale           string
+	StripeAutomaticTax             bool
+	StripeBillingAddressCollection string
+
+	// webhook settings
+	WebhookEnabledEvents []string
+	WebhookReplayWindow  time.Duration
+}
+
+// RateLimitTier is a number of attempts allowed per window
+type RateLimitTier struct {
+	Attempts int
+	Duration time.Duration
 }
 
 var AppConfig *Config
 
+// KnownRoles lists the user roles a configured default may refer to
+var KnownRoles = []string{"admin", "manager", "member", "read-only"}
+
+// What happens on login when a user already has MaxSessionsPerUser active sessions
+const (
+	SessionLimitEvict  = "evict"  // revoke the oldest session
+	SessionLimitReject = "reject" // refuse the new login
+)
+
+// How error responses are rendered
+const (
+	ErrorCodeFormatCode    = "code"    // stable machine-readable code alongside the message
+	ErrorCodeFormatMessage = "message" // message only, as before error codes existed
+)
+
+// How flagged high-impact admin operations are handled
+const (
+	AdminHighImpactAlert   = "alert"   // proceed, but email the other admins
+	AdminHighImpactConfirm = "confirm" // require a second confirmation token
+)
+
+// requestBodyFormMargin is added to the largest upload limit for the default MaxRequestBodySize
+const requestBodyFormMargin = 10 << 20 // 10MB
+
+// defaultAccessTokenSecret is public, so anything signed with it (OAuth state, cursors) can be forged
+const defaultAccessTokenSecret = "your-secret-key"
+
 func LoadConfig() {
 	AppConfig = &Config{
 		// Server
-		ServerPort: getEnvOrDefault("PORT", "8080"),
-		ServerHost: getEnvOrDefault("HOST", "localhost"),
+		ServerPort:                getEnvOrDefault("PORT", "8080"),
+		ServerHost:                getEnvOrDefault("HOST", "localhost"),
+		MaxConcurrentRequests:     getEnvAsInt("MAX_CONCURRENT_REQUESTS", 0), // 0 = unlimited
+		RequestTimeout:            getEnvAsDuration("REQUEST_TIMEOUT", "30s"),
+		RequestTimeoutExemptPaths: getEnvAsStringSlice("REQUEST_TIMEOUT_EXEMPT_PATHS", []string{"/api/v1/assets/export", "/api/v1/audit-logs/export", "/api/v1/assets/import"}),
+		JSONMaxDepth:              getEnvAsInt("JSON_MAX_DEPTH", 32),
+		JSONMaxFields:             getEnvAsInt("JSON_MAX_FIELDS", 1000),
+		CompressionEnabled:        getEnvAsBool("COMPRESSION_ENABLED", true),
+		CompressionMinSize:        getEnvAsInt("COMPRESSION_MIN_SIZE", 1024), // bytes
+		ETagEnabled:               getEnvAsBool("ETAG_ENABLED", true),
+		ErrorCodeFormat:           strings.ToLower(getEnvOrDefault("ERROR_CODE_FORMAT", ErrorCodeFormatCode)),
+		PaginationStyle:           strings.ToLower(getEnvOrDefault("PAGINATION_STYLE", PaginationOffset)),
+		DefaultSortOrders:         getEnvAsStringMap("DEFAULT_SORT", map[string]string{"assets": "created_at:desc", "users": "created_at:desc"}),
+		DeprecatedEndpoints:       getEnvAsStringMap("DEPRECATED_ENDPOINTS", map[string]string{}),
+		ResponseEnvelopeEnabled:   getEnvAsBool("RESPONSE_ENVELOPE", true),
+
+		ServerReadTimeout:       getEnvAsDuration("SERVER_READ_TIMEOUT", "15s"),
+		ServerWriteTimeout:      getEnvAsDuration("SERVER_WRITE_TIMEOUT", "30s"),
+		ServerIdleTimeout:       getEnvAsDuration("SERVER_IDLE_TIMEOUT", "60s"),
+		ServerReadHeaderTimeout: getEnvAsDuration("SERVER_READ_HEADER_TIMEOUT", "5s"),
+		LongRunningReadTimeout:  getEnvAsDuration("LONG_RUNNING_READ_TIMEOUT", "5m"), // exports and uploads, see ReadTimeoutFor
+		LongRunningWriteTimeout: getEnvAsDuration("LONG_RUNNING_WRITE_TIMEOUT", getEnvAsDuration("UPLOAD_WRITE_TIMEOUT", "5m").String()),
+		StartupWaitTimeout:      getEnvAsDuration("STARTUP_WAIT_TIMEOUT", "60s"),
+		StartupWaitBackoff:      getEnvAsDuration("STARTUP_WAIT_BACKOFF", "500ms"),
+
+		TLSMinVersion: getEnvOrDefault("TLS_MIN_VERSION", "1.2"),
+		TLSCertPath:   getEnvOrDefault("TLS_CERT_PATH", ""),
+		TLSKeyPath:    getEnvOrDefault("TLS_KEY_PATH", ""),
+
+		// Outbound HTTP client
+		HTTPClientTimeout:             getEnvAsDuration("HTTP_CLIENT_TIMEOUT", "30s"),
+		HTTPClientDialTimeout:         getEnvAsDuration("HTTP_CLIENT_DIAL_TIMEOUT", "5s"),
+		HTTPClientTLSHandshakeTimeout: getEnvAsDuration("HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT", "5s"),
+		HTTPClientMaxRetries:          getEnvAsInt("HTTP_CLIENT_MAX_RETRIES", 2),
+		HTTPClientRetryBackoff:        getEnvAsDuration("HTTP_CLIENT_RETRY_BACKOFF", "200ms"),
+
+		// Health check
+		HealthDegradedLatencyMs:      getEnvAsInt("HEALTH_DEGRADED_LATENCY_MS", 500),
+		HealthMaxConsecutiveFailures: getEnvAsInt("HEALTH_MAX_CONSECUTIVE_FAILURES", 3),
+		LatencyBudgetMs:              getEnvAsIntMap("LATENCY_BUDGET_MS", map[string]int{"default": 500}),
 
 		// google oauth
 		GoogleClientID:      getEnvOrDefault("GOOGLE_CLIENT_ID", "your-google-client-id"),
 		GoogleClientSecret:  getEnvOrDefault("GOOGLE_CLIENT_SECRET", "your-google-client-secret"),
 		GoogleRedirectURL:   getEnvOrDefault("GOOGLE_REDIRECT_URL", "http://localhost:5005/api/v1/users/google/callback"),
 		FrontendRedirectURL: getEnvOrDefault("FRONTEND_REDIRECT_URL", "http://localhost:5173"),
-
-		StripeWebhookSecret:  getEnvOrDefault("STRIPE_WEBHOOK_SECRET", "your-stripe-webhook-secret"),
-		StripeCancelUrlDev:   getEnvOrDefault("STRIPE_CANCEL_URL_DEV", "http://localhost:5173/checkout/cancel"),
-		StripeSuccessUrlDev:  getEnvOrDefault("STRIPE_SUCCESS_URL_DEV", "http://localhost:5173/checkout/success"),
-		StripeCancelUrlProd:  getEnvOrDefault("STRIPE_CANCEL_URL_PROD", "https://your-production-url/checkout/cancel"),
-		StripeSuccessUrlProd: getEnvOrDefault("STRIPE_SUCCESS_URL_PROD", "https://your-production-url/checkout/success"),
-		StripeSecretKey:      getEnvOrDefault("STRIPE_SECRET_KEY", "your-stripe-secret-key"),
-		StripePublishableKey: getEnvOrDefault("STRIPE_PUBLISHABLE_KEY", "your-stripe-publishable-key"),
+		OAuthStateTTL:       getEnvAsDuration("OAUTH_STATE_TTL", "10m"),
+
+		StripeWebhookSecret:            getEnvOrDefault("STRIPE_WEBHOOK_SECRET", "your-stripe-webhook-secret"),
+		StripeWebhookTolerance:         getEnvAsDuration("STRIPE_WEBHOOK_TOLERANCE", "5m"),
+		StripeCancelUrlDev:             getEnvOrDefault("STRIPE_CANCEL_URL_DEV", "http://localhost:5173/checkout/cancel"),
+		StripeSuccessUrlDev:            getEnvOrDefault("STRIPE_SUCCESS_URL_DEV", "http://localhost:5173/checkout/success"),
+		StripeCancelUrlProd:            getEnvOrDefault("STRIPE_CANCEL_URL_PROD", "https://your-production-url/checkout/cancel"),
+		StripeSuccessUrlProd:           getEnvOrDefault("STRIPE_SUCCESS_URL_PROD", "https://your-production-url/checkout/success"),
+		StripeSecretKey:                getEnvOrDefault("STRIPE_SECRET_KEY", "your-stripe-secret-key"),
+		StripePublishableKey:           getEnvOrDefault("STRIPE_PUBLISHABLE_KEY", "your-stripe-publishable-key"),
+		StripePortalReturnURL:          getEnvOrDefault("STRIPE_PORTAL_RETURN_URL", "http://localhost:5173/billing"),
+		StripePortalFeatures:           getEnvAsStringSlice("STRIPE_PORTAL_FEATURES", []string{"invoice_history", "payment_method_update"}),
+		StripeCheckoutLocale:           getEnvOrDefault("STRIPE_CHECKOUT_LOCALE", "auto"),
+		StripeAutomaticTax:             getEnvAsBool("STRIPE_AUTOMATIC_TAX", false),
+		StripeBillingAddressCollection: getEnvOrDefault("STRIPE_BILLING_ADDRESS_COLLECTION", "auto"),
+
+		// webhooks
+		WebhookEnabledEvents: getEnvAsStringSlice("WEBHOOK_ENABLED_EVENTS", nil),
+		WebhookReplayWindow:  getEnvAsDuration("WEBHOOK_REPLAY_WINDOW", "24h"),
 
 		// Security
-		CookieDomain:        getEnvOrDefault("COOKIE_DOMAIN", "localhost"),
-		ApiKeys:             getEnvOrDefault("API_KEY", "your-api-keys"),
-		RateLimitAttempts:   getEnvAsInt("RATE_LIMIT_ATTEMPTS", 100),
-		RateLimitDuration:   getEnvAsDuration("RATE_LIMIT_DURATION", "60s"),
+		CookieDomain:            getEnvOrDefault("COOKIE_DOMAIN", "localhost"),
+		TrackSessions:           getEnvAsBool("TRACK_SESSIONS", true),
+		MaxSessionsPerUser:      getEnvAsInt("MAX_SESSIONS_PER_USER", 0), // 0 = unlimited
+		SessionLimitMode:        strings.ToLower(getEnvOrDefault("SESSION_LIMIT_MODE", SessionLimitEvict)),
+		ApiKeys:                 getEnvOrDefault("API_KEY", "your-api-keys"),
+		RateLimitAttempts:       getEnvAsInt("RATE_LIMIT_ATTEMPTS", 100),
+		RateLimitDuration:       getEnvAsDuration("RATE_LIMIT_DURATION", "60s"),
+		RateLimitHeadersEnabled: getEnvAsBool("RATE_LIMIT_HEADERS", true),
+		ExportRateLimitAttempts: getEnvAsInt("EXPORT_RATE_LIMIT", 5),
+		ExportRateLimitDuration: getEnvAsDuration("EXPORT_RATE_LIMIT_DURATION", "1h"),
+		RateLimitExemptApiKeys:  getEnvAsStringSlice("RATE_LIMIT_EXEMPT_API_KEYS", nil),
+		TenantRateLimit: RateLimitTier{
+			Attempts: getEnvAsInt("TENANT_RATE_LIMIT_ATTEMPTS", 1000),
+			Duration: getEnvAsDuration("TENANT_RATE_LIMIT_DURATION", "60s"),
+		},
 		TrustedProxies:      getEnvAsStringSlice("TRUSTED_PROXIES", []string{"localhost"}),
 		SkippedApiEndpoints: getEnvAsStringSlice("SKIPPED_API_ENDPOINTS", []string{"/health"}),
 		AllowedOrigins:      getEnvAsStringSlice("ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
 
+		// Admin safeguards
+		AdminBulkDeleteThreshold: getEnvAsInt("ADMIN_BULK_DELETE_THRESHOLD", 50),
+		AdminHighImpactAction:    strings.ToLower(getEnvOrDefault("ADMIN_HIGH_IMPACT_ACTION", AdminHighImpactAlert)),
+		AdminIPAllowlist:         getEnvAsStringSlice("ADMIN_IP_ALLOWLIST", nil),
+
+		// Logging
+		AccessLogEnabled:      getEnvAsBool("ACCESS_LOG_ENABLED", true),
+		AccessLogRedactFields: getEnvAsStringSlice("ACCESS_LOG_REDACT_FIELDS", []string{"password", "token", "secret"}),
+
 		// Database
-		DatabaseRootURL: getEnvOrDefault("DB_ROOT_URL", "your-db-root-url"),
-		DatabaseName:    getEnvOrDefault("DB_NAME", "your-db-name"),
-		DatabaseURL:     getEnvOrDefault("DB_URL", "your-db-url"),
+		DatabaseRootURL:      getEnvOrDefault("DB_ROOT_URL", "your-db-root-url"),
+		DatabaseName:         getEnvOrDefault("DB_NAME", "your-db-name"),
+		DatabaseURL:          getEnvOrDefault("DB_URL", "your-db-url"),
+		DBLogQueries:         getEnvAsBool("DB_LOG_QUERIES", false),
+		DBSlowQueryThreshold: getEnvAsDuration("DB_SLOW_QUERY_THRESHOLD", getEnvAsDuration("SLOW_QUERY_THRESHOLD", "200ms").String()),
+		AnonymizeFields:      getEnvAsStringSlice("ANONYMIZE_FIELDS", []string{"users.email", "users.name", "assets.notes"}),
+
+		// Backup
+		BackupEnabled:       getEnvAsBool("BACKUP_ENABLED", false),
+		BackupSchedule:      getEnvOrDefault("BACKUP_SCHEDULE", "0 2 * * *"),
+		BackupTargetDir:     getEnvOrDefault("BACKUP_TARGET_DIR", "./backups"),
+		BackupRetentionDays: getEnvAsInt("BACKUP_RETENTION_DAYS", 7),
 
 		// Redis
 		RedisAddress:  getEnvOrDefault("REDIS_ADDRESS", "localhost:6379"),
 		RedisPassword: getEnvOrDefault("REDIS_PASSWORD", ""),
 
+		// Cache
+		DashboardCacheTTL: getEnvAsDuration("DASHBOARD_CACHE_TTL", "60s"),
+		CacheTTL:          getEnvAsDuration("CACHE_TTL", "5m"),
+
 		// JWT
-		AccessTokenSecret:  getEnvOrDefault("ACCESS_TOKEN_SECRET", "your-secret-key"),
-		RefreshTokenSecret: getEnvOrDefault("REFRESH_TOKEN_SECRET", "your-refresh-token-secret"),
+		AccessTokenSecret:     getEnvOrDefault("ACCESS_TOKEN_SECRET", defaultAccessTokenSecret),
+		RefreshTokenSecret:    getEnvOrDefault("REFRESH_TOKEN_SECRET", "your-refresh-token-secret"),
+		JWTAlgorithm:          strings.ToUpper(getEnvOrDefault("JWT_ALG", "HS256")),
+		JWTPrivateKeyPath:     getEnvOrDefault("JWT_PRIVATE_KEY_PATH", ""),
+		JWTPrivateKeyPEM:      getEnvOrDefault("JWT_PRIVATE_KEY", ""),
+		JWTPublicKeyPath:      getEnvOrDefault("JWT_PUBLIC_KEY_PATH", ""),
+		JWTPublicKeyPEM:       getEnvOrDefault("JWT_PUBLIC_KEY", ""),
+		RefreshTokenRotation:  getEnvAsBool("REFRESH_TOKEN_ROTATION", true),
+		SlidingSessionEnabled: getEnvAsBool("SLIDING_SESSION_ENABLED", false),
+		SlidingSessionWindow:  getEnvAsDuration("SLIDING_SESSION_WINDOW", "5m"),
+		TokenCleanupInterval:  getEnvAsDuration("TOKEN_CLEANUP_INTERVAL", "1h"),
+		PasswordResetTTL:      getEnvAsDuration("PASSWORD_RESET_TTL", "1h"),
+		AllowImpersonation:    getEnvAsBool("ALLOW_IMPERSONATION", false),
+		ImpersonationTTL:      getEnvAsDuration("IMPERSONATION_TTL", "15m"),
 
 		// mailer configuration
-		SMTPEmail:    getEnvOrDefault("SMTP_EMAIL", ""),
-		SMTPPort:     getEnvAsInt("SMTP_PORT", 587),
-		SMTPHost:     getEnvOrDefault("SMTP_HOST", ""),
-		SMTPPassword: getEnvOrDefault("SMTP_PASSWORD", ""),
+		SMTPEmail:          getEnvOrDefault("SMTP_EMAIL", ""),
+		SMTPPort:           getEnvAsInt("SMTP_PORT", 587),
+		SMTPHost:           getEnvOrDefault("SMTP_HOST", ""),
+		SMTPPassword:       getEnvOrDefault("SMTP_PASSWORD", ""),
+		EmailReplyTo:       getEnvOrDefault("EMAIL_REPLY_TO", getEnvOrDefault("SMTP_REPLY_TO", "")),
+		EmailBatchSize:     getEnvAsInt("EMAIL_BATCH_SIZE", 20),
+		EmailBatchInterval: getEnvAsDuration("EMAIL_BATCH_INTERVAL", "5s"),
 
 		// App
-		AppName:     getEnvOrDefault("APP_NAME", "Asset Management System"),
-		AppEnv:      getEnvOrDefault("APP_ENV", "development"),
-		FrontendURL: getEnvOrDefault("FRONTEND_URL", "http://localhost:5173"),
+		AppName:      getEnvOrDefault("APP_NAME", "Asset Management System"),
+		AppEnv:       getEnvOrDefault("APP_ENV", "development"),
+		FeatureFlags: getEnvAsBoolMap("FEATURE_FLAGS", map[string]bool{}),
+		FrontendURL:  getEnvOrDefault("FRONTEND_URL", "http://localhost:5173"),
+
+		// Media
+		DefaultAvatarURL:       getEnvOrDefault("DEFAULT_AVATAR_URL", ""),
+		DocumentPlaceholderURL: getEnvOrDefault("DOCUMENT_PLACEHOLDER_URL", ""),
+		VideoPlaceholderURL:    getEnvOrDefault("VIDEO_PLACEHOLDER_URL", ""),
+		GeneratePDFThumbnails:  getEnvAsBool("GENERATE_PDF_THUMBNAILS", false),
+
+		// Account
+		GDPRGraceDays:            getEnvAsInt("GDPR_GRACE_DAYS", 30),
+		DefaultUserStorageQuota:  getEnvAsInt64("USER_STORAGE_QUOTA", 1<<30), // 1GB
+		InvitationTTL:            getEnvAsDuration("INVITATION_TTL", "72h"),
+		RequireEmailVerification: getEnvAsBool("REQUIRE_EMAIL_VERIFICATION", true),
+		DefaultUserRole:          getEnvOrDefault("DEFAULT_USER_ROLE", "member"),
+
+		// Asset
+		WarrantyNotifyDaysBefore:     getEnvAsIntSlice("WARRANTY_NOTIFY_DAYS", []int{30, 7, 1}),
+		DefaultCurrency:              strings.ToUpper(getEnvOrDefault("DEFAULT_CURRENCY", "USD")),
+		BulkBatchSize:                getEnvAsInt("BULK_BATCH_SIZE", 100),
+		BulkConfirmThreshold:         getEnvAsInt("BULK_CONFIRM_THRESHOLD", 500),
+		DuplicateSimilarityThreshold: getEnvAsFloat64("DUPLICATE_SIMILARITY_THRESHOLD", 0.85),
+		LabelReprintAnomalyThreshold: getEnvAsInt("LABEL_REPRINT_ANOMALY_THRESHOLD", 3),
+		LabelReprintAnomalyWindow:    getEnvAsDuration("LABEL_REPRINT_ANOMALY_WINDOW", "720h"), // 30 days
+		AssetEditLockTTL:             getEnvAsDuration("ASSET_EDIT_LOCK_TTL", "5m"),
+		NestedCollectionLimit:        getEnvAsInt("NESTED_COLLECTION_LIMIT", 10),
+		BarcodeFormat:                strings.ToUpper(getEnvOrDefault("BARCODE_FORMAT", BarcodeCode128)),
+		MaintenanceReminderLeadTime:  getEnvAsDuration("MAINTENANCE_REMINDER_LEAD_TIME", "72h"),
+		RequireAssetApproval:         getEnvAsBool("REQUIRE_ASSET_APPROVAL", false),
+		AssetApproverRole:            getEnvOrDefault("ASSET_APPROVER_ROLE", "manager"),
+
+		AssetStatusTransitionsFile: getEnvOrDefault("ASSET_STATUS_TRANSITIONS_FILE", ""),
+
+		// Location
+		MaxLocationDepth: getEnvAsInt("MAX_LOCATION_DEPTH", 5),
+		GeofenceEnabled:  getEnvAsBool("GEOFENCE_ENABLED", false),
+		GeofenceMinLat:   getEnvAsFloat64("GEOFENCE_MIN_LAT", -90),
+		GeofenceMaxLat:   getEnvAsFloat64("GEOFENCE_MAX_LAT", 90),
+		GeofenceMinLng:   getEnvAsFloat64("GEOFENCE_MIN_LNG", -180),
+		GeofenceMaxLng:   getEnvAsFloat64("GEOFENCE_MAX_LNG", 180),
 
 		// Cloudinary
 		CloudName:   getEnvOrDefault("CLOUDINARY_CLOUD_NAME", "your-cloudinary-cloud-name"),
 		CloudSecret: getEnvOrDefault("CLOUDINARY_API_SECRET", "your-cloudinary-api-secret"),
 		CloudApiKey: getEnvOrDefault("CLOUDINARY_API_KEY", "your-cloudinary-api-key"),
 		CloudFolder: getEnvOrDefault("CLOUDINARY_FOLDER", "asset_management_app"),
+
+		CloudAsyncTransform:    getEnvAsBool("CLOUDINARY_ASYNC", false),
+		CloudNotificationURL:   getEnvOrDefault("CLOUDINARY_NOTIFICATION_URL", ""),
+		CloudAllowDirectUpload: getEnvAsBool("CLOUDINARY_DIRECT_UPLOAD", false),
 	}
 	AppConfig.AllowedImageTypes = getEnvAsStringSlice("ALLOWED_IMAGE_TYPES", []string{"image/jpeg", "image/png"})
 	AppConfig.AllowedVideoTypes = getEnvAsStringSlice("ALLOWED_VIDEO_TYPES", []string{"video/mp4"})
@@ -143,6 +480,39 @@ func LoadConfig() {
 		"videos":    getEnvAsInt64("MAX_VIDEO_SIZE", 100<<20),   // 100MB
 		"documents": getEnvAsInt64("MAX_DOCUMENT_SIZE", 10<<20), // 10MB
 	}
+	// defaults to the largest upload plus room for form fields, so raising a file size limit
+	// does not require raising MAX_REQUEST_BODY_SIZE too
+	AppConfig.MaxRequestBodySize = getEnvAsInt64("MAX_REQUEST_BODY_SIZE", slices.Max(slices.Collect(maps.Values(AppConfig.MaxFileSize)))+requestBodyFormMargin)
+	AppConfig.maxRequestBodySizeSet = os.Getenv("MAX_REQUEST_BODY_SIZE") != ""
+	AppConfig.UploadSniffContentType = getEnvAsBool("UPLOAD_SNIFF", true)
+	AppConfig.MaxImageWidth = getEnvAsInt("MAX_IMAGE_WIDTH", 4096)
+	AppConfig.MaxImageHeight = getEnvAsInt("MAX_IMAGE_HEIGHT", 4096)
+	AppConfig.ResizeOnUpload = getEnvAsBool("RESIZE_ON_UPLOAD", false)
+	AppConfig.DedupeUploads = getEnvAsBool("DEDUPE_UPLOADS", false)
+	AppConfig.MaxConcurrentUploads = getEnvAsInt("MAX_CONCURRENT_UPLOADS", 4)
+	AppConfig.UploadQueueSize = getEnvAsInt("UPLOAD_QUEUE_SIZE", 16)
+	AppConfig.PreviewMaxWidth = getEnvAsInt("PREVIEW_MAX_WIDTH", 1920)
+	AppConfig.PreviewMaxHeight = getEnvAsInt("PREVIEW_MAX_HEIGHT", 1080)
+
+	AppConfig.EmailFromName = getEnvOrDefault("EMAIL_FROM_NAME", getEnvOrDefault("SMTP_FROM_NAME", AppConfig.AppName))
+
+	AppConfig.DefaultSelfRegisterRole = getEnvOrDefault("DEFAULT_SELF_REGISTER_ROLE", AppConfig.DefaultUserRole)
+
+	AppConfig.JWTIssuer = getEnvOrDefault("JWT_ISSUER", AppConfig.AppName)
+	AppConfig.JWTAudience = getEnvOrDefault("JWT_AUDIENCE", "")
+
+	if err := AppConfig.validate(); err != nil {
+		log.Fatalf("❌ Invalid configuration: %v", err)
+	}
+	if err := AppConfig.loadJWTKeys(); err != nil {
+		log.Fatalf("❌ Failed to load JWT keys: %v", err)
+	}
+	if err := AppConfig.loadAssetStatusTransitions(); err != nil {
+		log.Fatalf("❌ Failed to load asset status transitions: %v", err)
+	}
+	if err := AppConfig.loadAdminIPAllowlist(); err != nil {
+		log.Fatalf("❌ Failed to load ADMIN_IP_ALLOWLIST: %v", err)
+	}
 	fmt.Println("✅ Global configuration load complete")
 }
 
@@ -172,6 +542,24 @@ func getEnvAsInt64(key string, defaultValue int64) int64 {
 	return defaultValue
 }
 
+func getEnvAsFloat64(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(key string, defaultValue string) time.Duration {
 	value := os.Getenv(key)
 	if value == "" {
@@ -202,10 +590,286 @@ func getEnvAsStringSlice(key string, defaultValue []string) []string {
 	return result
 }
 
+func getEnvAsIntSlice(key string, defaultValue []int) []int {
+	items := getEnvAsStringSlice(key, nil)
+	if items == nil {
+		return defaultValue
+	}
+
+	result := make([]int, 0, len(items))
+	for _, item := range items {
+		parsed, err := strconv.Atoi(item)
+		if err != nil {
+			return defaultValue
+		}
+		result = append(result, parsed)
+	}
+	return result
+}
+
+// getEnvAsStringMap parses "key=value" pairs separated by commas
+func getEnvAsStringMap(key string, defaultValue map[string]string) map[string]string {
+	items := getEnvAsStringSlice(key, nil)
+	if items == nil {
+		return defaultValue
+	}
+
+	result := make(map[string]string, len(items))
+	for _, item := range items {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return defaultValue
+		}
+		result[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return result
+}
+
+func getEnvAsBoolMap(key string, defaultValue map[string]bool) map[string]bool {
+	pairs := getEnvAsStringMap(key, nil)
+	if pairs == nil {
+		return defaultValue
+	}
+
+	result := make(map[string]bool, len(pairs))
+	for k, v := range pairs {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return defaultValue
+		}
+		result[k] = parsed
+	}
+	return result
+}
+
+func getEnvAsIntMap(key string, defaultValue map[string]int) map[string]int {
+	pairs := getEnvAsStringMap(key, nil)
+	if pairs == nil {
+		return defaultValue
+	}
+
+	result := make(map[string]int, len(pairs))
+	for k, v := range pairs {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return defaultValue
+		}
+		result[k] = parsed
+	}
+	return result
+}
+
 func GetServerAddress() string {
 	return AppConfig.ServerHost + ":" + AppConfig.ServerPort
 }
 
+// IsRequestTimeoutExempt reports whether a long-running route (exports, uploads) skips the request timeout
+func IsRequestTimeoutExempt(path string) bool {
+	for _, prefix := range AppConfig.RequestTimeoutExemptPaths {
+		// whole path segments only, so /assets/import does not also match /assets/imports-history
+		prefix = strings.TrimSuffix(prefix, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadTimeoutFor returns the read deadline for a route: LongRunningReadTimeout for the
+// long-running routes in RequestTimeoutExemptPaths (exports, uploads), ServerReadTimeout
+// otherwise. The server applies ServerReadTimeout to the whole body, so upload handlers
+// must extend it before reading with
+// http.NewResponseController(w).SetReadDeadline(time.Now().Add(ReadTimeoutFor(path))).
+func ReadTimeoutFor(path string) time.Duration {
+	if IsRequestTimeoutExempt(path) {
+		return AppConfig.LongRunningReadTimeout
+	}
+	return AppConfig.ServerReadTimeout
+}
+
+// WriteTimeoutFor is the write-deadline counterpart of ReadTimeoutFor, applied with
+// http.NewResponseController(w).SetWriteDeadline(time.Now().Add(WriteTimeoutFor(path))).
+func WriteTimeoutFor(path string) time.Duration {
+	if IsRequestTimeoutExempt(path) {
+		return AppConfig.LongRunningWriteTimeout
+	}
+	return AppConfig.ServerWriteTimeout
+}
+
+// BudgetFor returns the latency budget of a route category, falling back to the "default"
+// entry; zero means no budget applies
+func BudgetFor(category string) time.Duration {
+	ms, ok := AppConfig.LatencyBudgetMs[category]
+	if !ok {
+		ms = AppConfig.LatencyBudgetMs["default"]
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// SunsetFor returns the Sunset header value (an HTTP-date) for a deprecated endpoint path
+func SunsetFor(path string) (string, bool) {
+	date, ok := AppConfig.DeprecatedEndpoints[path]
+	if !ok {
+		return "", false
+	}
+	sunset, _ := time.Parse(time.DateOnly, date)
+	return sunset.UTC().Format(http.TimeFormat), true
+}
+
+// NewHTTPServer builds the http.Server with the configured timeouts and, when
+// TLS_CERT_PATH/TLS_KEY_PATH are set, the minimum TLS version
+func NewHTTPServer(handler http.Handler) *http.Server {
+	server := &http.Server{
+		Addr:              GetServerAddress(),
+		Handler:           handler,
+		ReadTimeout:       AppConfig.ServerReadTimeout,
+		WriteTimeout:      AppConfig.ServerWriteTimeout,
+		IdleTimeout:       AppConfig.ServerIdleTimeout,
+		ReadHeaderTimeout: AppConfig.ServerReadHeaderTimeout,
+	}
+	if TLSEnabled() {
+		server.TLSConfig = TLSConfig()
+	}
+	return server
+}
+
+// PlaceholderURLFor returns the preview placeholder for a media category without a generated preview
+func PlaceholderURLFor(category string) string {
+	switch category {
+	case "documents":
+		return AppConfig.DocumentPlaceholderURL
+	case "videos":
+		return AppConfig.VideoPlaceholderURL
+	}
+	return ""
+}
+
+// IsAllowedContentType reports whether contentType is allowed for the upload category (images, videos, documents)
+func IsAllowedContentType(category, contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return slices.Contains(allowedTypesFor(category), strings.ToLower(strings.TrimSpace(mediaType)))
+}
+
+func allowedTypesFor(category string) []string {
+	switch category {
+	case "images":
+		return AppConfig.AllowedImageTypes
+	case "videos":
+		return AppConfig.AllowedVideoTypes
+	case "documents":
+		return AppConfig.AllowedDocumentTypes
+	}
+	return nil
+}
+
+// MaxFileSizeFor returns the upload size limit for a category (images, videos, documents)
+func MaxFileSizeFor(category string) (int64, bool) {
+	limit, ok := AppConfig.MaxFileSize[category]
+	return limit, ok
+}
+
+// ImageExceedsMaxDimensions reports whether an image of the given size is larger than the configured bounds
+func ImageExceedsMaxDimensions(width, height int) bool {
+	return width > AppConfig.MaxImageWidth || height > AppConfig.MaxImageHeight
+}
+
+// IsRedactedField reports whether a query param or body field must be masked in access logs.
+// Matching is case-insensitive and by substring, so "token" also covers "access_token".
+func IsRedactedField(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range AppConfig.AccessLogRedactFields {
+		if strings.Contains(name, strings.ToLower(field)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHighImpactBulkDelete reports whether a bulk delete of count records exceeds
+// ADMIN_BULK_DELETE_THRESHOLD and must be flagged for review
+func IsHighImpactBulkDelete(count int) bool {
+	return count > AppConfig.AdminBulkDeleteThreshold
+}
+
+// EmailFrom returns the From header value for outgoing mail. A non-empty name
+// overrides EMAIL_FROM_NAME, for templates that send under a different identity.
+// It fails when SMTP_EMAIL is unset rather than building a header with no address.
+func EmailFrom(name string) (string, error) {
+	if AppConfig.SMTPEmail == "" {
+		return "", errors.New("SMTP_EMAIL is not configured")
+	}
+	if name == "" {
+		name = AppConfig.EmailFromName
+	}
+	return (&mail.Address{Name: name, Address: AppConfig.SMTPEmail}).String(), nil
+}
+
+// IsRateLimitExempt reports whether an API key belongs to an internal service account
+// that bypasses the rate limiter
+func IsRateLimitExempt(apiKey string) bool {
+	if apiKey == "" {
+		return false
+	}
+	for _, exempt := range AppConfig.RateLimitExemptApiKeys {
+		if subtle.ConstantTimeCompare([]byte(apiKey), []byte(exempt)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// TenantRateLimitKey returns the limiter key for a tenant's route, so one noisy tenant
+// cannot exhaust another's budget
+func TenantRateLimitKey(tenantID, route string) string {
+	return "tenant:" + tenantID + ":" + route
+}
+
+// IsWebhookEventEnabled reports whether the system emits an outbound webhook event type at all;
+// an empty WEBHOOK_ENABLED_EVENTS emits every event
+func IsWebhookEventEnabled(event string) bool {
+	return len(AppConfig.WebhookEnabledEvents) == 0 || slices.Contains(AppConfig.WebhookEnabledEvents, event)
+}
+
+// EnsureAnonymizationAllowed guards PII scrubbing against ever running on the production database
+func EnsureAnonymizationAllowed() error {
+	if IsProduction() {
+		return errors.New("anonymization is disabled when APP_ENV=production")
+	}
+	return nil
+}
+
+// WithinGeofence reports whether a coordinate lies inside the configured bounds;
+// it always passes when geofencing is disabled
+func WithinGeofence(lat, lng float64) bool {
+	if !AppConfig.GeofenceEnabled {
+		return true
+	}
+	return lat >= AppConfig.GeofenceMinLat && lat <= AppConfig.GeofenceMaxLat &&
+		lng >= AppConfig.GeofenceMinLng && lng <= AppConfig.GeofenceMaxLng
+}
+
+// ShouldSlideSession reports whether a valid access token expiring at expiresAt is close enough
+// to expiry to be reissued. The refresh token must still be live, and the reissued token's
+// expiry must be capped at refreshExpiresAt by the caller.
+func ShouldSlideSession(expiresAt, refreshExpiresAt time.Time) bool {
+	if !AppConfig.SlidingSessionEnabled {
+		return false
+	}
+	now := time.Now()
+	return now.Before(expiresAt) && expiresAt.Sub(now) <= AppConfig.SlidingSessionWindow && now.Before(refreshExpiresAt)
+}
+
+// FitPreview scales width x height down to fit within the preview bounds, preserving aspect
+// ratio; dimensions already within bounds are returned unchanged
+func FitPreview(width, height int) (int, int) {
+	if width <= AppConfig.PreviewMaxWidth && height <= AppConfig.PreviewMaxHeight {
+		return width, height
+	}
+
+	scale := min(float64(AppConfig.PreviewMaxWidth)/float64(width), float64(AppConfig.PreviewMaxHeight)/float64(height))
+	return max(1, int(float64(width)*scale)), max(1, int(float64(height)*scale))
+}
+
 func IsProduction() bool {
 	return AppConfig.AppEnv == "production"
 }
@@ -213,3 +877,11 @@ func IsProduction() bool {
 func IsDevelopment() bool {
 	return AppConfig.AppEnv == "development"
 }
+
+// AvatarOrDefault returns avatar, falling back to the configured placeholder when it is empty
+func AvatarOrDefault(avatar string) string {
+	if avatar == "" {
+		return AppConfig.DefaultAvatarURL
+	}
+	return avatar
+}
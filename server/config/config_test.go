@@ -0,0 +1,124 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEmailFrom(t *testing.T) {
+	AppConfig = &Config{SMTPEmail: "noreply@example.com", EmailFromName: "Asset Management System"}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", `"Asset Management System" <noreply@example.com>`},
+		{"Billing", `"Billing" <noreply@example.com>`},
+	}
+	for _, tt := range tests {
+		got, err := EmailFrom(tt.name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("EmailFrom(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEmailFromWithoutAddress(t *testing.T) {
+	AppConfig = &Config{EmailFromName: "Asset Management System"}
+
+	if got, err := EmailFrom(""); err == nil {
+		t.Errorf("EmailFrom() = %q, want an error when SMTP_EMAIL is unset", got)
+	}
+}
+
+func TestIsHighImpactBulkDelete(t *testing.T) {
+	AppConfig = &Config{AdminBulkDeleteThreshold: 50}
+
+	tests := []struct {
+		count int
+		want  bool
+	}{
+		{1, false},
+		{50, false},
+		{51, true},
+	}
+	for _, tt := range tests {
+		if got := IsHighImpactBulkDelete(tt.count); got != tt.want {
+			t.Errorf("IsHighImpactBulkDelete(%d) = %v, want %v", tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestIsRequestTimeoutExempt(t *testing.T) {
+	AppConfig = &Config{RequestTimeoutExemptPaths: []string{"/api/v1/assets/export", "/api/v1/assets/import/"}}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/api/v1/assets/export", true},
+		{"/api/v1/assets/export/123", true},
+		{"/api/v1/assets/exports-history", false},
+		{"/api/v1/assets/import", true},
+		{"/api/v1/assets/imports-history", false},
+		{"/api/v1/assets/import/csv", true},
+		{"/api/v1/assets", false},
+	}
+	for _, tt := range tests {
+		if got := IsRequestTimeoutExempt(tt.path); got != tt.want {
+			t.Errorf("IsRequestTimeoutExempt(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestShouldSlideSession(t *testing.T) {
+	now := time.Now()
+	refreshLive := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name             string
+		enabled          bool
+		expiresAt        time.Time
+		refreshExpiresAt time.Time
+		want             bool
+	}{
+		{"inside window", true, now.Add(2 * time.Minute), refreshLive, true},
+		{"outside window", true, now.Add(10 * time.Minute), refreshLive, false},
+		{"already expired", true, now.Add(-time.Minute), refreshLive, false},
+		{"refresh token expired", true, now.Add(2 * time.Minute), now.Add(-time.Minute), false},
+		{"feature disabled", false, now.Add(2 * time.Minute), refreshLive, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			AppConfig = &Config{SlidingSessionEnabled: tt.enabled, SlidingSessionWindow: 5 * time.Minute}
+			if got := ShouldSlideSession(tt.expiresAt, tt.refreshExpiresAt); got != tt.want {
+				t.Errorf("ShouldSlideSession() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageExceedsMaxDimensions(t *testing.T) {
+	AppConfig = &Config{MaxImageWidth: 4096, MaxImageHeight: 4096}
+
+	tests := []struct {
+		name          string
+		width, height int
+		want          bool
+	}{
+		{"within limits", 1920, 1080, false},
+		{"exactly at the limit", 4096, 4096, false},
+		{"one pixel too wide", 4097, 4096, true},
+		{"one pixel too tall", 4096, 4097, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ImageExceedsMaxDimensions(tt.width, tt.height); got != tt.want {
+				t.Errorf("ImageExceedsMaxDimensions(%d, %d) = %v, want %v", tt.width, tt.height, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var cronDescriptors = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+
+// cronField describes one standard cron field: minute, hour, day of month, month, day of week.
+// names are the three-letter aliases accepted in place of numbers, starting at lower.
+type cronField struct {
+	lower, upper int
+	names        []string
+}
+
+// day of week accepts both 0 and 7 for Sunday
+var cronFields = [5]cronField{
+	{lower: 0, upper: 59},
+	{lower: 0, upper: 23},
+	{lower: 1, upper: 31},
+	{lower: 1, upper: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{lower: 0, upper: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// validateCron checks a standard five-field cron expression or a predefined descriptor such as @daily
+func validateCron(expr string) error {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@") {
+		for _, descriptor := range cronDescriptors {
+			if expr == descriptor {
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown descriptor %q", expr)
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("expected %d fields, got %d", len(cronFields), len(fields))
+	}
+
+	for i, field := range fields {
+		for item := range strings.SplitSeq(field, ",") {
+			if err := validateCronItem(item, cronFields[i]); err != nil {
+				return fmt.Errorf("field %d (%q): %w", i+1, field, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateCronItem checks a single list item: *, n, a-b, optionally followed by /step
+func validateCronItem(item string, field cronField) error {
+	rangePart, stepPart, hasStep := strings.Cut(item, "/")
+	if hasStep {
+		step, err := strconv.Atoi(stepPart)
+		if err != nil || step <= 0 {
+			return fmt.Errorf("invalid step %q", stepPart)
+		}
+	}
+
+	if rangePart == "*" {
+		return nil
+	}
+
+	low, high, isRange := strings.Cut(rangePart, "-")
+	start, ok := field.parse(low)
+	if !ok {
+		return fmt.Errorf("value %q out of range %d-%d", low, field.lower, field.upper)
+	}
+	if !isRange {
+		return nil
+	}
+
+	end, ok := field.parse(high)
+	if !ok || end < start {
+		return fmt.Errorf("invalid range %q", rangePart)
+	}
+	return nil
+}
+
+// parse reads a number or a case-insensitive name and reports whether it is within bounds
+func (f cronField) parse(value string) (int, bool) {
+	for i, name := range f.names {
+		if strings.EqualFold(value, name) {
+			return f.lower + i, true
+		}
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < f.lower || n > f.upper {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,33 @@
+package config
+
+import "testing"
+
+func TestValidateCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{"0 2 * * *", false},
+		{"@daily", false},
+		{"*/15 9-17 * * 1-5", false},
+		{"0 2 * * 7", false},
+		{"0 2 * * 0,7", false},
+		{"0 2 * * MON-FRI", false},
+		{"0 2 * * sun", false},
+		{"0 0 1 JAN,JUL *", false},
+		{"0 2 * * 8", true},
+		{"0 2 * * FRI-MON", true},
+		{"0 0 1 JUL-MAR *", true},
+		{"60 2 * * *", true},
+		{"0 2 * *", true},
+		{"0 2 * * */0", true},
+		{"@sometimes", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if err := validateCron(tt.expr); (err != nil) != tt.wantErr {
+				t.Errorf("validateCron(%q) = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Supported PAGINATION_STYLE values
+const (
+	PaginationOffset = "offset"
+	PaginationCursor = "cursor"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the position after the last row of a page in cursor pagination.
+// ID breaks ties between rows sharing the same sort value.
+type Cursor struct {
+	SortField string `json:"f"`
+	Direction string `json:"d"`
+	Value     string `json:"v"`
+	ID        string `json:"i"`
+}
+
+// EncodeCursor returns an opaque cursor signed with ACCESS_TOKEN_SECRET so clients cannot tamper with it
+func EncodeCursor(cursor Cursor) string {
+	payload, _ := json.Marshal(cursor)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signCursor(encoded)
+}
+
+// DecodeCursor verifies and decodes a cursor produced by EncodeCursor
+func DecodeCursor(raw string) (Cursor, error) {
+	var cursor Cursor
+
+	encoded, signature, ok := strings.Cut(raw, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signCursor(encoded))) {
+		return cursor, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || json.Unmarshal(payload, &cursor) != nil {
+		return cursor, ErrInvalidCursor
+	}
+	if cursor.Direction != "asc" && cursor.Direction != "desc" {
+		return cursor, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+func signCursor(payload string) string {
+	mac := hmac.New(sha256.New, []byte("cursor:"+AppConfig.AccessTokenSecret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
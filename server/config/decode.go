@@ -0,0 +1,78 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	ErrJSONTooDeep = errors.New("json nesting too deep")
+	ErrJSONTooWide = errors.New("json has too many fields")
+)
+
+// DecodeJSONLimited decodes a request body into v after scanning its tokens against
+// JSON_MAX_DEPTH and JSON_MAX_FIELDS, so pathological payloads are rejected before the
+// full parse. Fields counts object members and array elements across the whole document.
+// The body size is expected to be bounded already, e.g. by http.MaxBytesReader.
+func DecodeJSONLimited(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err := checkJSONLimits(data, AppConfig.JSONMaxDepth, AppConfig.JSONMaxFields); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+type jsonFrame struct {
+	object    bool
+	expectKey bool
+}
+
+func checkJSONLimits(data []byte, maxDepth, maxFields int) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var stack []jsonFrame
+	fields := 0
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		delim, isDelim := tok.(json.Delim)
+		if isDelim && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		if len(stack) > 0 {
+			parent := &stack[len(stack)-1]
+			if parent.object && parent.expectKey {
+				// a key, its value follows
+				parent.expectKey = false
+				continue
+			}
+			parent.expectKey = parent.object
+
+			fields++
+			if fields > maxFields {
+				return fmt.Errorf("%w: limit is %d", ErrJSONTooWide, maxFields)
+			}
+		}
+
+		if isDelim {
+			stack = append(stack, jsonFrame{object: delim == '{', expectKey: true})
+			if len(stack) > maxDepth {
+				return fmt.Errorf("%w: limit is %d", ErrJSONTooDeep, maxDepth)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSONLimited(t *testing.T) {
+	AppConfig = &Config{JSONMaxDepth: 3, JSONMaxFields: 5}
+
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{"within limits", `{"name":"laptop","meta":{"tags":["a","b"]}}`, nil},
+		{"at max depth", `{"a":{"b":{"c":1}}}`, nil},
+		{"too deep", `{"a":{"b":{"c":{"d":1}}}}`, ErrJSONTooDeep},
+		{"too deep arrays", `[[[[1]]]]`, ErrJSONTooDeep},
+		{"at max fields", `{"a":1,"b":2,"c":3,"d":4,"e":5}`, nil},
+		{"too many fields", `{"a":1,"b":2,"c":3,"d":4,"e":5,"f":6}`, ErrJSONTooWide},
+		{"too many array elements", `[1,2,3,4,5,6]`, ErrJSONTooWide},
+		{"nested fields count", `{"a":[1,2],"b":{"c":1,"d":2}}`, ErrJSONTooWide},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			err := DecodeJSONLimited(strings.NewReader(tt.body), &v)
+			if tt.want == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("DecodeJSONLimited() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONLimitedDecodes(t *testing.T) {
+	AppConfig = &Config{JSONMaxDepth: 3, JSONMaxFields: 5}
+
+	var v struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	if err := DecodeJSONLimited(strings.NewReader(`{"name":"laptop","tags":["a","b"]}`), &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Name != "laptop" || len(v.Tags) != 2 {
+		t.Errorf("decoded %+v", v)
+	}
+}
+
+func TestDecodeJSONLimitedMalformed(t *testing.T) {
+	AppConfig = &Config{JSONMaxDepth: 3, JSONMaxFields: 5}
+
+	var v any
+	if err := DecodeJSONLimited(strings.NewReader(`{"a":`), &v); err == nil {
+		t.Error("expected an error for malformed JSON")
+	}
+}
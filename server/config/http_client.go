@@ -0,0 +1,57 @@
+package config
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// NewHTTPClient builds the shared client for outbound calls (OAuth, Stripe, Cloudinary)
+// so a slow dependency cannot hang a request indefinitely. Idempotent GETs are retried
+// with exponential backoff on network errors and 5xx responses.
+func NewHTTPClient() *http.Client {
+	dialer := &net.Dialer{Timeout: AppConfig.HTTPClientDialTimeout, KeepAlive: 30 * time.Second}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.TLSHandshakeTimeout = AppConfig.HTTPClientTLSHandshakeTimeout
+
+	return &http.Client{
+		Transport: &retryTransport{
+			next:       transport,
+			maxRetries: AppConfig.HTTPClientMaxRetries,
+			backoff:    AppConfig.HTTPClientRetryBackoff,
+		},
+		Timeout: AppConfig.HTTPClientTimeout,
+	}
+}
+
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	backoff    time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.next.RoundTrip(req)
+	}
+
+	delay := t.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.maxRetries || (err == nil && resp.StatusCode < http.StatusInternalServerError) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestHTTPClientConfig() *Config {
+	return &Config{
+		HTTPClientTimeout:             100 * time.Millisecond,
+		HTTPClientDialTimeout:         time.Second,
+		HTTPClientTLSHandshakeTimeout: time.Second,
+		HTTPClientMaxRetries:          2,
+		HTTPClientRetryBackoff:        time.Millisecond,
+	}
+}
+
+func TestNewHTTPClientTimeout(t *testing.T) {
+	AppConfig = newTestHTTPClientConfig()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	start := time.Now()
+	_, err := NewHTTPClient().Post(server.URL, "text/plain", strings.NewReader("ping"))
+
+	var netErr interface{ Timeout() bool }
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request took %s, timeout did not fire", elapsed)
+	}
+}
+
+func TestNewHTTPClientRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		failures   int32
+		wantStatus int
+		wantHits   int32
+	}{
+		{"GET recovers after 5xx", http.MethodGet, 2, http.StatusOK, 3},
+		{"GET gives up after max retries", http.MethodGet, 5, http.StatusBadGateway, 3},
+		{"POST is not retried", http.MethodPost, 1, http.StatusBadGateway, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			AppConfig = newTestHTTPClientConfig()
+
+			var hits atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if hits.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusBadGateway)
+				}
+			}))
+			defer server.Close()
+
+			req, _ := http.NewRequest(tt.method, server.URL, nil)
+			resp, err := NewHTTPClient().Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("server hit %d times, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	JWTAlgHS256 = "HS256"
+	JWTAlgRS256 = "RS256"
+)
+
+// loadJWTKeys parses the RSA key pair used for RS256 signing. The public key
+// falls back to the private key's public half when not configured separately.
+func (c *Config) loadJWTKeys() error {
+	if c.JWTAlgorithm != JWTAlgRS256 {
+		return nil
+	}
+
+	privatePEM, err := readPEM(c.JWTPrivateKeyPath, c.JWTPrivateKeyPEM)
+	if err != nil {
+		return fmt.Errorf("private key: %w", err)
+	}
+	privateKey, err := parseRSAPrivateKey(privatePEM)
+	if err != nil {
+		return fmt.Errorf("private key: %w", err)
+	}
+	c.JWTPrivateKey = privateKey
+
+	if c.JWTPublicKeyPath == "" && c.JWTPublicKeyPEM == "" {
+		c.JWTPublicKey = &privateKey.PublicKey
+		return nil
+	}
+
+	publicPEM, err := readPEM(c.JWTPublicKeyPath, c.JWTPublicKeyPEM)
+	if err != nil {
+		return fmt.Errorf("public key: %w", err)
+	}
+	publicKey, err := parseRSAPublicKey(publicPEM)
+	if err != nil {
+		return fmt.Errorf("public key: %w", err)
+	}
+	if !publicKey.Equal(&privateKey.PublicKey) {
+		return errors.New("public key does not match private key")
+	}
+	c.JWTPublicKey = publicKey
+	return nil
+}
+
+// readPEM prefers the key file when set, otherwise the inline PEM (which may use escaped newlines)
+func readPEM(path, inline string) ([]byte, error) {
+	if path != "" {
+		return os.ReadFile(path)
+	}
+	return []byte(strings.ReplaceAll(inline, `\n`, "\n")), nil
+}
+
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA private key")
+	}
+	return key, nil
+}
+
+func parseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("not an RSA public key")
+	}
+	return key, nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func generateTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return key
+}
+
+func encodePEM(blockType string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
+
+func TestLoadJWTKeys(t *testing.T) {
+	key := generateTestRSAKey(t)
+	other := generateTestRSAKey(t)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkix, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs1Private := encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+	pkcs8Private := encodePEM("PRIVATE KEY", pkcs8)
+	pkcs1Public := encodePEM("RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&key.PublicKey))
+	pkixPublic := encodePEM("PUBLIC KEY", pkix)
+	otherPublic := encodePEM("RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&other.PublicKey))
+
+	privatePath := filepath.Join(t.TempDir(), "private.pem")
+	if err := os.WriteFile(privatePath, []byte(pkcs8Private), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{
+			name:   "PKCS1 private key falls back to its public half",
+			config: Config{JWTPrivateKeyPEM: pkcs1Private},
+		},
+		{
+			name:   "PKCS8 private key with PKIX public key",
+			config: Config{JWTPrivateKeyPEM: pkcs8Private, JWTPublicKeyPEM: pkixPublic},
+		},
+		{
+			name:   "PKCS1 public key",
+			config: Config{JWTPrivateKeyPEM: pkcs1Private, JWTPublicKeyPEM: pkcs1Public},
+		},
+		{
+			name:   "private key file takes precedence over inline PEM",
+			config: Config{JWTPrivateKeyPath: privatePath, JWTPrivateKeyPEM: "not a key"},
+		},
+		{
+			name:   "inline PEM with escaped newlines",
+			config: Config{JWTPrivateKeyPEM: strings.ReplaceAll(pkcs1Private, "\n", `\n`)},
+		},
+		{
+			name:    "mismatched public key",
+			config:  Config{JWTPrivateKeyPEM: pkcs1Private, JWTPublicKeyPEM: otherPublic},
+			wantErr: "does not match",
+		},
+		{
+			name:    "missing private key",
+			config:  Config{},
+			wantErr: "no PEM block found",
+		},
+		{
+			name:    "public key given as private key",
+			config:  Config{JWTPrivateKeyPEM: pkixPublic},
+			wantErr: "private key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.config
+			c.JWTAlgorithm = JWTAlgRS256
+
+			err := c.loadJWTKeys()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadJWTKeys() = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !c.JWTPrivateKey.Equal(key) {
+				t.Error("private key does not match the generated key")
+			}
+			if !c.JWTPublicKey.Equal(&key.PublicKey) {
+				t.Error("public key does not match the generated key")
+			}
+		})
+	}
+}
+
+func TestLoadJWTKeysSkipsHS256(t *testing.T) {
+	c := Config{JWTAlgorithm: JWTAlgHS256, JWTPrivateKeyPEM: "not a key"}
+
+	if err := c.loadJWTKeys(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.JWTPrivateKey != nil || c.JWTPublicKey != nil {
+		t.Error("keys should not be loaded for HS256")
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrOAuthStateInvalid = errors.New("invalid oauth state")
+	ErrOAuthStateExpired = errors.New("oauth state expired")
+)
+
+const oauthStateNonceSize = 16
+
+// OAuthStateCookieName holds the state between the redirect and the callback
+const OAuthStateCookieName = "oauth_state"
+
+// GenerateOAuthState returns a signed state value of the form payload.signature,
+// where the payload carries a random nonce and an expiry OAuthStateTTL from now.
+// Callers must bind it to the browser with SetOAuthStateCookie and store
+// OAuthStateNonce(state) in Redis with the same TTL so a state can only be redeemed once.
+func GenerateOAuthState() string {
+	payload := make([]byte, oauthStateNonceSize+8)
+	rand.Read(payload[:oauthStateNonceSize])
+	binary.BigEndian.PutUint64(payload[oauthStateNonceSize:], uint64(time.Now().Add(AppConfig.OAuthStateTTL).Unix()))
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signOAuthState(encoded)
+}
+
+// VerifyOAuthState checks the signature and expiry of a state produced by GenerateOAuthState
+func VerifyOAuthState(state string) error {
+	encoded, signature, ok := strings.Cut(state, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signOAuthState(encoded))) {
+		return ErrOAuthStateInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(payload) != oauthStateNonceSize+8 {
+		return ErrOAuthStateInvalid
+	}
+
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[oauthStateNonceSize:])), 0)
+	if time.Now().After(expiresAt) {
+		return ErrOAuthStateExpired
+	}
+	return nil
+}
+
+// OAuthStateNonce returns the nonce part of a state, used as its Redis key
+func OAuthStateNonce(state string) string {
+	encoded, _, _ := strings.Cut(state, ".")
+	if len(encoded) < base64.RawURLEncoding.EncodedLen(oauthStateNonceSize) {
+		return encoded
+	}
+	return encoded[:base64.RawURLEncoding.EncodedLen(oauthStateNonceSize)]
+}
+
+// SetOAuthStateCookie binds a state to the browser that starts the flow. Without it an
+// attacker could start a flow, then send the victim the callback URL carrying the
+// attacker's code and state, logging the victim into the attacker's account.
+func SetOAuthStateCookie(w http.ResponseWriter, state string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     OAuthStateCookieName,
+		Value:    state,
+		Path:     "/",
+		Domain:   AppConfig.CookieDomain,
+		MaxAge:   int(AppConfig.OAuthStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   IsProduction(),
+		SameSite: http.SameSiteLaxMode, // the provider redirects back with a top-level GET
+	})
+}
+
+// VerifyOAuthCallback checks the callback's state query param against the cookie set by
+// SetOAuthStateCookie and then its signature and expiry. The cookie is cleared either way.
+// It returns the state so the caller can look up and delete its nonce in Redis.
+func VerifyOAuthCallback(w http.ResponseWriter, r *http.Request) (string, error) {
+	http.SetCookie(w, &http.Cookie{Name: OAuthStateCookieName, Path: "/", Domain: AppConfig.CookieDomain, MaxAge: -1})
+
+	state := r.URL.Query().Get("state")
+	cookie, err := r.Cookie(OAuthStateCookieName)
+	if err != nil || state == "" || !hmac.Equal([]byte(cookie.Value), []byte(state)) {
+		return "", ErrOAuthStateInvalid
+	}
+	if err := VerifyOAuthState(state); err != nil {
+		return "", err
+	}
+	return state, nil
+}
+
+func signOAuthState(payload string) string {
+	mac := hmac.New(sha256.New, []byte("oauth-state:"+AppConfig.AccessTokenSecret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// GeneratePKCEVerifier returns a random RFC 7636 code verifier (43 URL-safe characters).
+// Store it in Redis under the state nonce, so it is only reachable through the cookie-bound
+// state, and send PKCEChallenge(verifier) on the redirect.
+func GeneratePKCEVerifier() string {
+	buf := make([]byte, 32)
+	rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// PKCEChallenge derives the S256 code challenge for a verifier
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestPKCEChallenge(t *testing.T) {
+	// RFC 7636 Appendix B
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	if got := PKCEChallenge(verifier); got != want {
+		t.Errorf("PKCEChallenge(%q) = %q, want %q", verifier, got, want)
+	}
+}
+
+func TestGeneratePKCEVerifier(t *testing.T) {
+	verifier := GeneratePKCEVerifier()
+	if len(verifier) != 43 {
+		t.Errorf("verifier length = %d, want 43", len(verifier))
+	}
+	if verifier == GeneratePKCEVerifier() {
+		t.Error("two verifiers should not be equal")
+	}
+}
+
+func TestVerifyOAuthState(t *testing.T) {
+	AppConfig = &Config{AccessTokenSecret: "test-secret", OAuthStateTTL: 10 * time.Minute}
+
+	state := GenerateOAuthState()
+	tampered := []byte(state)
+	if tampered[0] == 'A' {
+		tampered[0] = 'B'
+	} else {
+		tampered[0] = 'A'
+	}
+
+	tests := []struct {
+		name  string
+		state string
+		want  error
+	}{
+		{"valid", state, nil},
+		{"missing", "", ErrOAuthStateInvalid},
+		{"no signature", OAuthStateNonce(state), ErrOAuthStateInvalid},
+		{"tampered payload", string(tampered), ErrOAuthStateInvalid},
+		{"bad signature", state + "x", ErrOAuthStateInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyOAuthState(tt.state); !errors.Is(err, tt.want) {
+				t.Errorf("VerifyOAuthState() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyOAuthStateExpired(t *testing.T) {
+	AppConfig = &Config{AccessTokenSecret: "test-secret", OAuthStateTTL: -time.Minute}
+
+	if err := VerifyOAuthState(GenerateOAuthState()); !errors.Is(err, ErrOAuthStateExpired) {
+		t.Errorf("VerifyOAuthState() = %v, want %v", err, ErrOAuthStateExpired)
+	}
+}
+
+func TestVerifyOAuthStateWrongSecret(t *testing.T) {
+	AppConfig = &Config{AccessTokenSecret: "test-secret", OAuthStateTTL: 10 * time.Minute}
+	state := GenerateOAuthState()
+
+	AppConfig = &Config{AccessTokenSecret: "rotated-secret", OAuthStateTTL: 10 * time.Minute}
+	if err := VerifyOAuthState(state); !errors.Is(err, ErrOAuthStateInvalid) {
+		t.Errorf("VerifyOAuthState() = %v, want %v", err, ErrOAuthStateInvalid)
+	}
+}
+
+func TestVerifyOAuthCallback(t *testing.T) {
+	AppConfig = &Config{AccessTokenSecret: "test-secret", OAuthStateTTL: 10 * time.Minute, CookieDomain: "localhost"}
+
+	state := GenerateOAuthState()
+	redirect := httptest.NewRecorder()
+	SetOAuthStateCookie(redirect, state)
+	cookie := redirect.Result().Cookies()[0]
+	if !cookie.HttpOnly || cookie.Value != state {
+		t.Fatalf("unexpected state cookie %+v", cookie)
+	}
+
+	attackerState := GenerateOAuthState()
+
+	tests := []struct {
+		name   string
+		query  string
+		cookie *http.Cookie
+		want   error
+	}{
+		{"matching cookie", state, cookie, nil},
+		{"missing cookie", state, nil, ErrOAuthStateInvalid},
+		{"missing state", "", cookie, ErrOAuthStateInvalid},
+		{"state from another browser", attackerState, cookie, ErrOAuthStateInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/users/google/callback?state="+url.QueryEscape(tt.query), nil)
+			if tt.cookie != nil {
+				r.AddCookie(tt.cookie)
+			}
+			w := httptest.NewRecorder()
+
+			got, err := VerifyOAuthCallback(w, r)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("VerifyOAuthCallback() = %v, want %v", err, tt.want)
+			}
+			if err == nil && got != state {
+				t.Errorf("VerifyOAuthCallback() returned state %q, want %q", got, state)
+			}
+			if cleared := w.Result().Cookies(); len(cleared) != 1 || cleared[0].MaxAge >= 0 {
+				t.Errorf("state cookie was not cleared: %+v", cleared)
+			}
+		})
+	}
+}
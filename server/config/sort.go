@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const fallbackSortOrder = "created_at:desc"
+
+var sortFieldPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// DefaultSortFor returns the ORDER BY clause applied when a list request has no sort param.
+// id is always appended as a tiebreaker so paging over equal sort values is stable.
+func DefaultSortFor(entity string) string {
+	order, ok := AppConfig.DefaultSortOrders[entity]
+	if !ok {
+		order = fallbackSortOrder
+	}
+
+	field, direction, _ := strings.Cut(order, ":")
+	direction = strings.ToUpper(direction)
+	if field == "id" {
+		return "id " + direction
+	}
+	return field + " " + direction + ", id " + direction
+}
+
+func validateSortOrder(order string) error {
+	field, direction, ok := strings.Cut(order, ":")
+	if !ok || !sortFieldPattern.MatchString(field) {
+		return fmt.Errorf("expected field:direction, got %q", order)
+	}
+	if direction != "asc" && direction != "desc" {
+		return fmt.Errorf("direction must be asc or desc, got %q", direction)
+	}
+	return nil
+}
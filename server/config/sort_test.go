@@ -0,0 +1,48 @@
+package config
+
+import "testing"
+
+func TestDefaultSortFor(t *testing.T) {
+	AppConfig = &Config{DefaultSortOrders: map[string]string{
+		"assets":    "name:asc",
+		"users":     "created_at:desc",
+		"locations": "id:asc",
+	}}
+
+	tests := []struct {
+		entity string
+		want   string
+	}{
+		{"assets", "name ASC, id ASC"},
+		{"users", "created_at DESC, id DESC"},
+		{"locations", "id ASC"},
+		{"invoices", "created_at DESC, id DESC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entity, func(t *testing.T) {
+			if got := DefaultSortFor(tt.entity); got != tt.want {
+				t.Errorf("DefaultSortFor(%q) = %q, want %q", tt.entity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSortOrder(t *testing.T) {
+	tests := []struct {
+		order   string
+		wantErr bool
+	}{
+		{"name:asc", false},
+		{"created_at:desc", false},
+		{"name", true},
+		{"name:up", true},
+		{"name;drop table:asc", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			if err := validateSortOrder(tt.order); (err != nil) != tt.wantErr {
+				t.Errorf("validateSortOrder(%q) = %v, wantErr %v", tt.order, err, tt.wantErr)
+			}
+		})
+	}
+}
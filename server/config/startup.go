@@ -0,0 +1,34 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+const maxStartupBackoff = 10 * time.Second
+
+// WaitForDependency pings a dependency (database, Redis) until it answers, backing off
+// exponentially from StartupWaitBackoff, and gives up after StartupWaitTimeout
+func WaitForDependency(name string, ping func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), AppConfig.StartupWaitTimeout)
+	defer cancel()
+
+	delay := AppConfig.StartupWaitBackoff
+	for attempt := 1; ; attempt++ {
+		err := ping(ctx)
+		if err == nil {
+			fmt.Printf("✅ %s is available\n", name)
+			return nil
+		}
+		log.Printf("⏳ Waiting for %s (attempt %d): %v", name, attempt, err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s still unavailable after %s: %w", name, AppConfig.StartupWaitTimeout, err)
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxStartupBackoff)
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitForDependencyBecomesAvailable(t *testing.T) {
+	AppConfig = &Config{StartupWaitTimeout: 2 * time.Second, StartupWaitBackoff: 5 * time.Millisecond}
+
+	attempts := 0
+	err := WaitForDependency("database", func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("pinged %d times, want 3", attempts)
+	}
+}
+
+func TestWaitForDependencyTimeout(t *testing.T) {
+	AppConfig = &Config{StartupWaitTimeout: 50 * time.Millisecond, StartupWaitBackoff: 5 * time.Millisecond}
+
+	pingErr := errors.New("connection refused")
+	start := time.Now()
+	err := WaitForDependency("redis", func(ctx context.Context) error {
+		return pingErr
+	})
+
+	if !errors.Is(err, pingErr) {
+		t.Fatalf("error = %v, want it to wrap %v", err, pingErr)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("gave up after %s, want about %s", elapsed, AppConfig.StartupWaitTimeout)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrStripeSignatureInvalid = errors.New("invalid stripe signature")
+	ErrStripeSignatureStale   = errors.New("stripe event timestamp outside tolerance")
+)
+
+// VerifyStripeSignature checks a webhook payload against its Stripe-Signature header
+// (t=<unix>,v1=<hex>[,v1=...]) using STRIPE_WEBHOOK_SECRET, and rejects events signed
+// more than STRIPE_WEBHOOK_TOLERANCE ago so a captured request cannot be replayed
+func VerifyStripeSignature(payload []byte, header string) error {
+	var timestamp string
+	var signatures []string
+	for part := range strings.SplitSeq(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrStripeSignatureInvalid
+	}
+
+	expected := signStripePayload(timestamp, payload)
+	valid := false
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			valid = true
+		}
+	}
+	if !valid {
+		return ErrStripeSignatureInvalid
+	}
+
+	if time.Since(time.Unix(unix, 0)) > AppConfig.StripeWebhookTolerance {
+		return ErrStripeSignatureStale
+	}
+	return nil
+}
+
+func signStripePayload(timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(AppConfig.StripeWebhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
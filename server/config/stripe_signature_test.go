@@ -0,0 +1,51 @@
+package config
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func stripeHeader(signedAt time.Time, payload []byte) string {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + signStripePayload(timestamp, payload)
+}
+
+func TestVerifyStripeSignature(t *testing.T) {
+	AppConfig = &Config{StripeWebhookSecret: "whsec_test", StripeWebhookTolerance: 5 * time.Minute}
+	payload := []byte(`{"id":"evt_1","type":"invoice.paid"}`)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		payload []byte
+		header  string
+		want    error
+	}{
+		{"recent event", payload, stripeHeader(now, payload), nil},
+		{"recent event with extra v0 and v1", payload, stripeHeader(now, payload) + ",v0=deadbeef,v1=deadbeef", nil},
+		{"stale event", payload, stripeHeader(now.Add(-10*time.Minute), payload), ErrStripeSignatureStale},
+		{"tampered payload", []byte(`{"id":"evt_1","type":"invoice.voided"}`), stripeHeader(now, payload), ErrStripeSignatureInvalid},
+		{"tampered signature", payload, "t=" + strconv.FormatInt(now.Unix(), 10) + ",v1=deadbeef", ErrStripeSignatureInvalid},
+		{"missing header", payload, "", ErrStripeSignatureInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyStripeSignature(tt.payload, tt.header); !errors.Is(err, tt.want) {
+				t.Errorf("VerifyStripeSignature() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyStripeSignatureWrongSecret(t *testing.T) {
+	AppConfig = &Config{StripeWebhookSecret: "whsec_test", StripeWebhookTolerance: 5 * time.Minute}
+	payload := []byte(`{"id":"evt_1"}`)
+	header := stripeHeader(time.Now(), payload)
+
+	AppConfig.StripeWebhookSecret = "whsec_other"
+	if err := VerifyStripeSignature(payload, header); !errors.Is(err, ErrStripeSignatureInvalid) {
+		t.Errorf("VerifyStripeSignature() = %v, want %v", err, ErrStripeSignatureInvalid)
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSEnabled reports whether the server terminates TLS itself
+func TLSEnabled() bool {
+	return AppConfig.TLSCertPath != "" && AppConfig.TLSKeyPath != ""
+}
+
+// TLSConfig returns the server TLS settings with the configured minimum version
+func TLSConfig() *tls.Config {
+	return &tls.Config{MinVersion: tlsVersions[AppConfig.TLSMinVersion]}
+}
+
+func (c *Config) validateTLS() error {
+	version, ok := tlsVersions[c.TLSMinVersion]
+	if !ok {
+		return fmt.Errorf("TLS_MIN_VERSION must be one of 1.0, 1.1, 1.2, 1.3, got %q", c.TLSMinVersion)
+	}
+	if c.AppEnv == "production" && version < tls.VersionTLS12 {
+		return fmt.Errorf("TLS_MIN_VERSION must be at least 1.2 in production, got %s", c.TLSMinVersion)
+	}
+	if (c.TLSCertPath == "") != (c.TLSKeyPath == "") {
+		return fmt.Errorf("TLS_CERT_PATH and TLS_KEY_PATH must be set together")
+	}
+	return nil
+}
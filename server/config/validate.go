@@ -0,0 +1,367 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// stripePortalFeatures lists the billing portal features Stripe accepts in a portal configuration
+var stripePortalFeatures = []string{
+	"customer_update",
+	"invoice_history",
+	"payment_method_update",
+	"subscription_cancel",
+	"subscription_update",
+}
+
+// stripeCheckoutLocales lists the locales Stripe Checkout accepts; "auto" detects from the browser
+var stripeCheckoutLocales = []string{
+	"auto", "bg", "cs", "da", "de", "el", "en", "en-GB", "es", "es-419", "et", "fi", "fil",
+	"fr", "fr-CA", "hr", "hu", "id", "it", "ja", "ko", "lt", "lv", "ms", "mt", "nb", "nl",
+	"pl", "pt", "pt-BR", "ro", "ru", "sk", "sl", "sv", "th", "tr", "vi", "zh", "zh-HK", "zh-TW",
+}
+
+// validate checks the loaded configuration for values the server cannot run with
+func (c *Config) validate() error {
+	if c.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("MAX_CONCURRENT_REQUESTS must be >= 0, got %d", c.MaxConcurrentRequests)
+	}
+	if c.CompressionMinSize < 0 {
+		return fmt.Errorf("COMPRESSION_MIN_SIZE must be >= 0, got %d", c.CompressionMinSize)
+	}
+	if c.GDPRGraceDays < 0 {
+		return fmt.Errorf("GDPR_GRACE_DAYS must be >= 0, got %d", c.GDPRGraceDays)
+	}
+	if c.AdminBulkDeleteThreshold <= 0 {
+		return fmt.Errorf("ADMIN_BULK_DELETE_THRESHOLD must be positive, got %d", c.AdminBulkDeleteThreshold)
+	}
+	if c.AdminHighImpactAction != AdminHighImpactAlert && c.AdminHighImpactAction != AdminHighImpactConfirm {
+		return fmt.Errorf("ADMIN_HIGH_IMPACT_ACTION must be %s or %s, got %q", AdminHighImpactAlert, AdminHighImpactConfirm, c.AdminHighImpactAction)
+	}
+
+	if c.DefaultAvatarURL != "" && !isAbsoluteURL(c.DefaultAvatarURL) {
+		return fmt.Errorf("DEFAULT_AVATAR_URL must be an absolute URL, got %q", c.DefaultAvatarURL)
+	}
+	if !isAbsoluteURL(c.StripePortalReturnURL) {
+		return fmt.Errorf("STRIPE_PORTAL_RETURN_URL must be an absolute URL, got %q", c.StripePortalReturnURL)
+	}
+	for _, feature := range c.StripePortalFeatures {
+		if !slices.Contains(stripePortalFeatures, feature) {
+			return fmt.Errorf("STRIPE_PORTAL_FEATURES contains unknown feature %q", feature)
+		}
+	}
+	if c.CloudAsyncTransform && !isAbsoluteURL(c.CloudNotificationURL) {
+		return fmt.Errorf("CLOUDINARY_ASYNC requires CLOUDINARY_NOTIFICATION_URL to be an absolute URL, got %q", c.CloudNotificationURL)
+	}
+	if c.MaxImageWidth <= 0 || c.MaxImageHeight <= 0 {
+		return fmt.Errorf("MAX_IMAGE_WIDTH and MAX_IMAGE_HEIGHT must be positive, got %dx%d", c.MaxImageWidth, c.MaxImageHeight)
+	}
+	if c.DefaultUserStorageQuota <= 0 {
+		return fmt.Errorf("USER_STORAGE_QUOTA must be positive, got %d", c.DefaultUserStorageQuota)
+	}
+
+	for _, days := range c.WarrantyNotifyDaysBefore {
+		if days <= 0 {
+			return fmt.Errorf("WARRANTY_NOTIFY_DAYS must only contain positive days, got %d", days)
+		}
+	}
+
+	if !isCurrencyCode(c.DefaultCurrency) {
+		return fmt.Errorf("DEFAULT_CURRENCY must be a three-letter uppercase code, got %q", c.DefaultCurrency)
+	}
+
+	if c.BackupEnabled {
+		if err := validateCron(c.BackupSchedule); err != nil {
+			return fmt.Errorf("BACKUP_SCHEDULE is not a valid cron expression: %w", err)
+		}
+		if c.BackupTargetDir == "" {
+			return fmt.Errorf("BACKUP_TARGET_DIR is required when BACKUP_ENABLED is set")
+		}
+		if c.BackupRetentionDays <= 0 {
+			return fmt.Errorf("BACKUP_RETENTION_DAYS must be positive, got %d", c.BackupRetentionDays)
+		}
+	}
+
+	switch c.JWTAlgorithm {
+	case JWTAlgHS256:
+	case JWTAlgRS256:
+		if c.JWTPrivateKeyPath == "" && c.JWTPrivateKeyPEM == "" {
+			return fmt.Errorf("JWT_ALG=RS256 requires JWT_PRIVATE_KEY_PATH or JWT_PRIVATE_KEY")
+		}
+	default:
+		return fmt.Errorf("JWT_ALG must be %s or %s, got %q", JWTAlgHS256, JWTAlgRS256, c.JWTAlgorithm)
+	}
+
+	// OAuth state and pagination cursors are signed with ACCESS_TOKEN_SECRET even under RS256
+	if c.AccessTokenSecret == defaultAccessTokenSecret && (c.AppEnv == "production" || c.JWTAlgorithm == JWTAlgRS256) {
+		return fmt.Errorf("ACCESS_TOKEN_SECRET must be changed from its default in production and when JWT_ALG=RS256")
+	}
+
+	if c.InvitationTTL <= 0 {
+		return fmt.Errorf("INVITATION_TTL must be positive, got %s", c.InvitationTTL)
+	}
+
+	if c.OAuthStateTTL <= 0 {
+		return fmt.Errorf("OAUTH_STATE_TTL must be positive, got %s", c.OAuthStateTTL)
+	}
+
+	if !slices.Contains(stripeCheckoutLocales, c.StripeCheckoutLocale) {
+		return fmt.Errorf("STRIPE_CHECKOUT_LOCALE is not a locale supported by Stripe Checkout: %q", c.StripeCheckoutLocale)
+	}
+
+	if c.SMTPEmail != "" {
+		if _, err := mail.ParseAddress(c.SMTPEmail); err != nil {
+			return fmt.Errorf("SMTP_EMAIL is not a valid address: %w", err)
+		}
+	}
+	if c.EmailReplyTo != "" {
+		if _, err := mail.ParseAddress(c.EmailReplyTo); err != nil {
+			return fmt.Errorf("EMAIL_REPLY_TO/SMTP_REPLY_TO is not a valid address: %w", err)
+		}
+	}
+
+	if c.HealthDegradedLatencyMs <= 0 {
+		return fmt.Errorf("HEALTH_DEGRADED_LATENCY_MS must be positive, got %d", c.HealthDegradedLatencyMs)
+	}
+	if c.HealthMaxConsecutiveFailures <= 0 {
+		return fmt.Errorf("HEALTH_MAX_CONSECUTIVE_FAILURES must be positive, got %d", c.HealthMaxConsecutiveFailures)
+	}
+
+	if c.ExportRateLimitAttempts <= 0 || c.ExportRateLimitDuration <= 0 {
+		return fmt.Errorf("EXPORT_RATE_LIMIT and EXPORT_RATE_LIMIT_DURATION must be positive, got %d per %s", c.ExportRateLimitAttempts, c.ExportRateLimitDuration)
+	}
+
+	if c.DashboardCacheTTL <= 0 {
+		return fmt.Errorf("DASHBOARD_CACHE_TTL must be positive, got %s", c.DashboardCacheTTL)
+	}
+
+	for name, timeout := range map[string]time.Duration{
+		"SERVER_READ_TIMEOUT":        c.ServerReadTimeout,
+		"SERVER_WRITE_TIMEOUT":       c.ServerWriteTimeout,
+		"SERVER_IDLE_TIMEOUT":        c.ServerIdleTimeout,
+		"SERVER_READ_HEADER_TIMEOUT": c.ServerReadHeaderTimeout,
+		"LONG_RUNNING_READ_TIMEOUT":  c.LongRunningReadTimeout,
+		"LONG_RUNNING_WRITE_TIMEOUT": c.LongRunningWriteTimeout,
+	} {
+		if timeout <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", name, timeout)
+		}
+	}
+
+	if c.StripeWebhookTolerance <= 0 {
+		return fmt.Errorf("STRIPE_WEBHOOK_TOLERANCE must be positive, got %s", c.StripeWebhookTolerance)
+	}
+
+	if c.AllowImpersonation && c.ImpersonationTTL <= 0 {
+		return fmt.Errorf("IMPERSONATION_TTL must be positive, got %s", c.ImpersonationTTL)
+	}
+
+	if c.HTTPClientTimeout <= 0 || c.HTTPClientDialTimeout <= 0 || c.HTTPClientTLSHandshakeTimeout <= 0 {
+		return fmt.Errorf("HTTP_CLIENT_TIMEOUT, HTTP_CLIENT_DIAL_TIMEOUT and HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT must be positive")
+	}
+	if c.HTTPClientMaxRetries < 0 {
+		return fmt.Errorf("HTTP_CLIENT_MAX_RETRIES must be >= 0, got %d", c.HTTPClientMaxRetries)
+	}
+
+	if c.BulkBatchSize <= 0 {
+		return fmt.Errorf("BULK_BATCH_SIZE must be positive, got %d", c.BulkBatchSize)
+	}
+
+	if c.MaintenanceReminderLeadTime <= 0 {
+		return fmt.Errorf("MAINTENANCE_REMINDER_LEAD_TIME must be positive, got %s", c.MaintenanceReminderLeadTime)
+	}
+
+	if c.StartupWaitTimeout <= 0 || c.StartupWaitBackoff <= 0 {
+		return fmt.Errorf("STARTUP_WAIT_TIMEOUT and STARTUP_WAIT_BACKOFF must be positive")
+	}
+
+	if c.ErrorCodeFormat != ErrorCodeFormatCode && c.ErrorCodeFormat != ErrorCodeFormatMessage {
+		return fmt.Errorf("ERROR_CODE_FORMAT must be %s or %s, got %q", ErrorCodeFormatCode, ErrorCodeFormatMessage, c.ErrorCodeFormat)
+	}
+
+	if c.TenantRateLimit.Attempts <= 0 || c.TenantRateLimit.Duration <= 0 {
+		return fmt.Errorf("TENANT_RATE_LIMIT_ATTEMPTS and TENANT_RATE_LIMIT_DURATION must be positive")
+	}
+
+	// the default is derived from the file size limits, so only an explicit value can be too small
+	if c.maxRequestBodySizeSet {
+		for category, limit := range c.MaxFileSize {
+			if limit > c.MaxRequestBodySize {
+				return fmt.Errorf("MAX_REQUEST_BODY_SIZE (%d) is smaller than the %s limit (%d)", c.MaxRequestBodySize, category, limit)
+			}
+		}
+	}
+
+	if c.RequestTimeout <= 0 {
+		return fmt.Errorf("REQUEST_TIMEOUT must be positive, got %s", c.RequestTimeout)
+	}
+
+	if c.TokenCleanupInterval < time.Minute {
+		return fmt.Errorf("TOKEN_CLEANUP_INTERVAL must be at least 1m, got %s", c.TokenCleanupInterval)
+	}
+
+	if !slices.Contains(KnownRoles, c.DefaultUserRole) {
+		return fmt.Errorf("DEFAULT_USER_ROLE must be one of %v, got %q", KnownRoles, c.DefaultUserRole)
+	}
+	if !slices.Contains(KnownRoles, c.DefaultSelfRegisterRole) {
+		return fmt.Errorf("DEFAULT_SELF_REGISTER_ROLE must be one of %v, got %q", KnownRoles, c.DefaultSelfRegisterRole)
+	}
+
+	if c.RequireAssetApproval && !slices.Contains(KnownRoles, c.AssetApproverRole) {
+		return fmt.Errorf("ASSET_APPROVER_ROLE must be one of %v, got %q", KnownRoles, c.AssetApproverRole)
+	}
+
+	if c.PasswordResetTTL <= 0 {
+		return fmt.Errorf("PASSWORD_RESET_TTL must be positive, got %s", c.PasswordResetTTL)
+	}
+
+	if c.MaxSessionsPerUser < 0 {
+		return fmt.Errorf("MAX_SESSIONS_PER_USER must be >= 0, got %d", c.MaxSessionsPerUser)
+	}
+	if c.SessionLimitMode != SessionLimitEvict && c.SessionLimitMode != SessionLimitReject {
+		return fmt.Errorf("SESSION_LIMIT_MODE must be %s or %s, got %q", SessionLimitEvict, SessionLimitReject, c.SessionLimitMode)
+	}
+
+	if c.DBSlowQueryThreshold <= 0 {
+		return fmt.Errorf("DB_SLOW_QUERY_THRESHOLD must be positive, got %s", c.DBSlowQueryThreshold)
+	}
+
+	if c.DuplicateSimilarityThreshold <= 0 || c.DuplicateSimilarityThreshold > 1 {
+		return fmt.Errorf("DUPLICATE_SIMILARITY_THRESHOLD must be in (0, 1], got %v", c.DuplicateSimilarityThreshold)
+	}
+
+	if err := c.validateTLS(); err != nil {
+		return err
+	}
+
+	if c.LabelReprintAnomalyThreshold <= 0 || c.LabelReprintAnomalyWindow <= 0 {
+		return fmt.Errorf("LABEL_REPRINT_ANOMALY_THRESHOLD and LABEL_REPRINT_ANOMALY_WINDOW must be positive")
+	}
+
+	if c.WebhookReplayWindow <= 0 {
+		return fmt.Errorf("WEBHOOK_REPLAY_WINDOW must be positive, got %s", c.WebhookReplayWindow)
+	}
+
+	for _, field := range c.AnonymizeFields {
+		if table, column, ok := strings.Cut(field, "."); !ok || table == "" || column == "" {
+			return fmt.Errorf("ANONYMIZE_FIELDS entries must be table.column, got %q", field)
+		}
+	}
+
+	if c.MaxLocationDepth <= 0 {
+		return fmt.Errorf("MAX_LOCATION_DEPTH must be positive, got %d", c.MaxLocationDepth)
+	}
+	if c.GeofenceEnabled {
+		if c.GeofenceMinLat < -90 || c.GeofenceMaxLat > 90 || c.GeofenceMinLat >= c.GeofenceMaxLat {
+			return fmt.Errorf("GEOFENCE_MIN_LAT/GEOFENCE_MAX_LAT must be an increasing range within -90..90")
+		}
+		if c.GeofenceMinLng < -180 || c.GeofenceMaxLng > 180 || c.GeofenceMinLng >= c.GeofenceMaxLng {
+			return fmt.Errorf("GEOFENCE_MIN_LNG/GEOFENCE_MAX_LNG must be an increasing range within -180..180")
+		}
+	}
+
+	if c.AssetEditLockTTL <= 0 {
+		return fmt.Errorf("ASSET_EDIT_LOCK_TTL must be positive, got %s", c.AssetEditLockTTL)
+	}
+
+	if c.NestedCollectionLimit <= 0 {
+		return fmt.Errorf("NESTED_COLLECTION_LIMIT must be positive, got %d", c.NestedCollectionLimit)
+	}
+
+	for category, ms := range c.LatencyBudgetMs {
+		if ms <= 0 {
+			return fmt.Errorf("LATENCY_BUDGET_MS budget for %q must be positive, got %d", category, ms)
+		}
+	}
+
+	if c.MaxConcurrentUploads <= 0 {
+		return fmt.Errorf("MAX_CONCURRENT_UPLOADS must be positive, got %d", c.MaxConcurrentUploads)
+	}
+	if c.UploadQueueSize < 0 {
+		return fmt.Errorf("UPLOAD_QUEUE_SIZE must be >= 0, got %d", c.UploadQueueSize)
+	}
+
+	if c.JSONMaxDepth <= 0 || c.JSONMaxFields <= 0 {
+		return fmt.Errorf("JSON_MAX_DEPTH and JSON_MAX_FIELDS must be positive, got %d and %d", c.JSONMaxDepth, c.JSONMaxFields)
+	}
+
+	if c.StripeBillingAddressCollection != "auto" && c.StripeBillingAddressCollection != "required" {
+		return fmt.Errorf("STRIPE_BILLING_ADDRESS_COLLECTION must be auto or required, got %q", c.StripeBillingAddressCollection)
+	}
+	if c.StripeAutomaticTax && c.StripeBillingAddressCollection != "required" {
+		return fmt.Errorf("STRIPE_AUTOMATIC_TAX requires STRIPE_BILLING_ADDRESS_COLLECTION=required so Stripe Tax has a customer address")
+	}
+
+	if c.BarcodeFormat != BarcodeCode128 && c.BarcodeFormat != BarcodeEAN13 {
+		return fmt.Errorf("BARCODE_FORMAT must be %s or %s, got %q", BarcodeCode128, BarcodeEAN13, c.BarcodeFormat)
+	}
+	if c.CacheTTL <= 0 {
+		return fmt.Errorf("CACHE_TTL must be positive, got %s", c.CacheTTL)
+	}
+
+	if c.PaginationStyle != PaginationOffset && c.PaginationStyle != PaginationCursor {
+		return fmt.Errorf("PAGINATION_STYLE must be %s or %s, got %q", PaginationOffset, PaginationCursor, c.PaginationStyle)
+	}
+
+	if c.SlidingSessionEnabled && c.SlidingSessionWindow <= 0 {
+		return fmt.Errorf("SLIDING_SESSION_WINDOW must be positive, got %s", c.SlidingSessionWindow)
+	}
+
+	for name, value := range map[string]string{
+		"DOCUMENT_PLACEHOLDER_URL": c.DocumentPlaceholderURL,
+		"VIDEO_PLACEHOLDER_URL":    c.VideoPlaceholderURL,
+	} {
+		if value != "" && !isAbsoluteURL(value) {
+			return fmt.Errorf("%s must be an absolute URL, got %q", name, value)
+		}
+	}
+
+	for entity, order := range c.DefaultSortOrders {
+		if err := validateSortOrder(order); err != nil {
+			return fmt.Errorf("DEFAULT_SORT for %q: %w", entity, err)
+		}
+	}
+
+	if c.BulkConfirmThreshold <= 0 {
+		return fmt.Errorf("BULK_CONFIRM_THRESHOLD must be positive, got %d", c.BulkConfirmThreshold)
+	}
+
+	if c.EmailBatchSize <= 0 || c.EmailBatchInterval <= 0 {
+		return fmt.Errorf("EMAIL_BATCH_SIZE and EMAIL_BATCH_INTERVAL must be positive, got %d and %s", c.EmailBatchSize, c.EmailBatchInterval)
+	}
+
+	for path, date := range c.DeprecatedEndpoints {
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return fmt.Errorf("DEPRECATED_ENDPOINTS sunset date for %q must be YYYY-MM-DD, got %q", path, date)
+		}
+	}
+
+	if c.PreviewMaxWidth <= 0 || c.PreviewMaxHeight <= 0 {
+		return fmt.Errorf("PREVIEW_MAX_WIDTH and PREVIEW_MAX_HEIGHT must be positive, got %dx%d", c.PreviewMaxWidth, c.PreviewMaxHeight)
+	}
+
+	return nil
+}
+
+// isCurrencyCode checks the shape of a currency code (three uppercase letters), not
+// membership in ISO 4217
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAbsoluteURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.IsAbs() && u.Host != ""
+}